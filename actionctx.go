@@ -0,0 +1,40 @@
+package kingpin
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ActionCtx is an Action receiving the context supplied to
+// Application.ParseWithContext. Register it with the ActionCtx() and
+// PreActionCtx() methods of the application, commands, flags and arguments,
+// or convert it to an Action with WithContext.
+type ActionCtx func(ctx context.Context, pc *ParseContext) error
+
+// WithContext converts an ActionCtx to an Action that can be supplied to
+// Action() or PreAction() of the application, commands, flags and arguments.
+//
+//	cmd.PostAction(kingpin.WithContext(func(ctx context.Context, pc *kingpin.ParseContext) error {
+//		return server.Shutdown(ctx)
+//	}))
+func WithContext(action ActionCtx) Action {
+	return func(pc *ParseContext) error {
+		return action(pc.Context(), pc)
+	}
+}
+
+// SignalContext returns a copy of parent that is cancelled when one of the
+// signals is received, or when the returned stop function is called. If no
+// signal is specified, os.Interrupt and SIGTERM are used.
+//
+//	ctx, stop := kingpin.SignalContext(context.Background())
+//	defer stop()
+//	kingpin.MustParse(app.ParseWithContext(ctx, os.Args[1:]))
+func SignalContext(parent context.Context, signals ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	return signal.NotifyContext(parent, signals...)
+}
@@ -0,0 +1,96 @@
+package kingpin
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey string
+
+func TestParseWithContext(t *testing.T) {
+	app := newTestApp()
+	var values []interface{}
+	collect := func(ctx context.Context, pc *ParseContext) error {
+		values = append(values, ctx.Value(contextKey("key")))
+		return nil
+	}
+	app.PreActionCtx(collect).Action(WithContext(collect))
+	cmd := app.Command("cmd", "").ActionCtx(collect)
+	cmd.Flag("flag", "").ActionCtx(collect).Bool()
+	cmd.Arg("arg", "").PreActionCtx(collect).String()
+
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+	_, err := app.ParseWithContext(ctx, []string{"cmd", "--flag", "arg"})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"value", "value", "value", "value", "value"}, values)
+
+	values = nil
+	_, err = app.Parse([]string{"cmd"})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{nil, nil, nil}, values)
+}
+
+func TestParseWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	app := newTestApp()
+	var calls int
+	cmd := app.Command("cmd", "")
+	cmd.Action(func(*ParseContext) error {
+		calls++
+		cancel()
+		return nil
+	})
+	cmd.Action(func(*ParseContext) error {
+		calls++
+		return nil
+	})
+	_, err := app.ParseWithContext(ctx, []string{"cmd"})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSignalContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the current process")
+	}
+	ctx, stop := SignalContext(context.Background())
+	defer stop()
+	process, _ := os.FindProcess(os.Getpid())
+	assert.NoError(t, process.Signal(os.Interrupt))
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by the signal")
+	}
+}
+
+func TestSignalContextCancelsAction(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the current process")
+	}
+	ctx, stop := SignalContext(context.Background())
+	defer stop()
+	app := newTestApp()
+	started := make(chan struct{})
+	app.Command("serve", "").ActionCtx(func(ctx context.Context, pc *ParseContext) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+	go func() {
+		<-started
+		process, _ := os.FindProcess(os.Getpid())
+		_ = process.Signal(os.Interrupt)
+	}()
+	_, err := app.ParseWithContext(ctx, []string{"serve"})
+	assert.Equal(t, context.Canceled, err)
+}
@@ -5,14 +5,21 @@ package kingpin
 // actions.
 type Action func(*ParseContext) error
 
+// ErrorAction callback executed when an action fails. It receives the error
+// returned by the action.
+type ErrorAction func(*ParseContext, error)
+
 type actionMixin struct {
-	actions    []Action
-	preActions []Action
+	actions      []Action
+	preActions   []Action
+	postActions  []Action
+	errorActions []ErrorAction
 }
 
 type actionApplier interface {
 	applyActions(*ParseContext) error
 	applyPreActions(*ParseContext) error
+	applyPostActions(*ParseContext, error) error
 }
 
 func (a *actionMixin) addAction(action Action) {
@@ -23,8 +30,19 @@ func (a *actionMixin) addPreAction(action Action) {
 	a.preActions = append(a.preActions, action)
 }
 
+func (a *actionMixin) addPostAction(action Action) {
+	a.postActions = append(a.postActions, action)
+}
+
+func (a *actionMixin) addErrorAction(action ErrorAction) {
+	a.errorActions = append(a.errorActions, action)
+}
+
 func (a *actionMixin) applyActions(context *ParseContext) error {
 	for _, action := range a.actions {
+		if err := context.Context().Err(); err != nil {
+			return err
+		}
 		if err := action(context); err != nil {
 			return err
 		}
@@ -34,9 +52,29 @@ func (a *actionMixin) applyActions(context *ParseContext) error {
 
 func (a *actionMixin) applyPreActions(context *ParseContext) error {
 	for _, preAction := range a.preActions {
+		if err := context.Context().Err(); err != nil {
+			return err
+		}
 		if err := preAction(context); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// Calls the error actions if err is not nil, then the post actions, in the
+// reverse order of their registration. The first error returned by a post
+// action is returned.
+func (a *actionMixin) applyPostActions(context *ParseContext, err error) (postErr error) {
+	if err != nil {
+		for i := len(a.errorActions) - 1; i >= 0; i-- {
+			a.errorActions[i](context, err)
+		}
+	}
+	for i := len(a.postActions) - 1; i >= 0; i-- {
+		if err := a.postActions[i](context); err != nil && postErr == nil {
+			postErr = err
+		}
+	}
+	return
+}
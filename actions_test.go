@@ -0,0 +1,137 @@
+package kingpin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostActions(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) Action {
+		return func(*ParseContext) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+	onError := func(name string) ErrorAction {
+		return func(_ *ParseContext, err error) {
+			calls = append(calls, name+": "+err.Error())
+		}
+	}
+	newApp := func(cmdErr error) *Application {
+		app := newTestApp()
+		app.Action(record("app", nil)).PostAction(record("app post", nil)).OnError(onError("app"))
+		cmd := app.Command("cmd", "").Action(record("cmd", cmdErr))
+		cmd.PostAction(record("cmd post 1", nil)).PostAction(record("cmd post 2", nil)).OnError(onError("cmd"))
+		cmd.Flag("flag", "").PostAction(record("flag post", nil)).Bool()
+		cmd.Arg("arg", "").Action(record("arg", nil)).PostAction(record("arg post", nil)).String()
+		return app
+	}
+
+	_, err := newApp(nil).Parse([]string{"cmd", "--flag", "value"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "cmd", "arg", "arg post", "flag post", "cmd post 2", "cmd post 1", "app post"}, calls)
+
+	calls = nil
+	_, err = newApp(errors.New("failed")).Parse([]string{"cmd", "value"})
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, []string{"app", "cmd", "arg post", "cmd: failed", "cmd post 2", "cmd post 1", "app: failed", "app post"}, calls)
+}
+
+func TestPostActionError(t *testing.T) {
+	app := newTestApp()
+	app.PostAction(func(*ParseContext) error { return errors.New("app post") })
+	app.Command("cmd", "").PostAction(func(*ParseContext) error { return errors.New("cmd post") })
+	_, err := app.Parse([]string{"cmd"})
+	assert.EqualError(t, err, "cmd post")
+
+	app = newTestApp()
+	app.Action(func(*ParseContext) error { return errors.New("action") })
+	app.PostAction(func(*ParseContext) error { return errors.New("post") })
+	_, err = app.Parse(nil)
+	assert.EqualError(t, err, "action")
+}
+
+func TestPostActionsAfterValidationFailure(t *testing.T) {
+	var calls []string
+	newApp := func() *Application {
+		app := newTestApp()
+		app.PostAction(func(*ParseContext) error {
+			calls = append(calls, "app post")
+			return nil
+		})
+		app.OnError(func(_ *ParseContext, err error) { calls = append(calls, "app: "+err.Error()) })
+		cmd := app.Command("cmd", "")
+		cmd.PostAction(func(*ParseContext) error {
+			calls = append(calls, "cmd post")
+			return nil
+		})
+		cmd.OnError(func(_ *ParseContext, err error) { calls = append(calls, "cmd: "+err.Error()) })
+		cmd.Flag("required", "").Required().String()
+		return app
+	}
+
+	_, err := newApp().Parse([]string{"cmd"})
+	assert.EqualError(t, err, "required flag(s) '--required' not provided")
+	assert.Equal(t, []string{"cmd: required flag(s) '--required' not provided", "cmd post", "app: required flag(s) '--required' not provided", "app post"}, calls)
+
+	calls = nil
+	app := newApp()
+	app.GetCommand("cmd").Validate(func(*CmdClause) error { return errors.New("invalid") })
+	_, err = app.Parse([]string{"cmd", "--required=value"})
+	assert.EqualError(t, err, "invalid")
+	assert.Equal(t, []string{"cmd: invalid", "cmd post", "app: invalid", "app post"}, calls)
+}
+
+func TestPostActionsAfterPreActionFailure(t *testing.T) {
+	var calls []string
+	app := newTestApp()
+	app.PostAction(func(*ParseContext) error {
+		calls = append(calls, "app post")
+		return nil
+	})
+	cmd := app.Command("cmd", "").Action(func(*ParseContext) error {
+		calls = append(calls, "cmd")
+		return nil
+	})
+	cmd.OnError(func(_ *ParseContext, err error) { calls = append(calls, "cmd: "+err.Error()) })
+	flag := cmd.Flag("flag", "").PreAction(func(*ParseContext) error { return errors.New("pre-action") })
+	flag.OnError(func(_ *ParseContext, err error) { calls = append(calls, "flag: "+err.Error()) })
+	flag.Bool()
+	cmd.Arg("arg", "").PostAction(func(*ParseContext) error {
+		calls = append(calls, "arg post")
+		return nil
+	}).String()
+
+	_, err := app.Parse([]string{"cmd", "--flag", "value"})
+	assert.EqualError(t, err, "pre-action")
+	assert.Equal(t, []string{"flag: pre-action", "cmd: pre-action", "app post"}, calls)
+}
+
+func TestPostActionsOfNestedCommands(t *testing.T) {
+	var calls []string
+	app := newTestApp()
+	app.OnError(func(_ *ParseContext, err error) { calls = append(calls, "app: "+err.Error()) })
+	app.PostAction(func(*ParseContext) error {
+		calls = append(calls, "app post")
+		return errors.New("app post")
+	})
+	nodes := app.Command("nodes", "")
+	nodes.OnError(func(_ *ParseContext, err error) { calls = append(calls, "nodes: "+err.Error()) })
+	nodes.PostAction(func(*ParseContext) error {
+		calls = append(calls, "nodes post")
+		return errors.New("nodes post")
+	})
+	nodes.Flag("zone", "").OnError(func(_ *ParseContext, err error) { calls = append(calls, "zone: "+err.Error()) }).String()
+	add := nodes.Command("add", "")
+	add.OnError(func(_ *ParseContext, err error) { calls = append(calls, "add: "+err.Error()) })
+	name := add.Arg("name", "")
+	name.OnError(func(_ *ParseContext, err error) { calls = append(calls, "name: "+err.Error()) })
+	name.Action(func(*ParseContext) error { return errors.New("invalid name") }).String()
+
+	_, err := app.Parse([]string{"nodes", "--zone=eu", "add", "n1"})
+	assert.EqualError(t, err, "invalid name")
+	assert.Equal(t, []string{"name: invalid name", "add: invalid name", "nodes: invalid name", "nodes post", "app: invalid name", "app post"}, calls)
+}
@@ -48,12 +48,16 @@ func (f *FlagClause) setAutoShortcut(value bool) *FlagClause {
 
 // Add an alias to the selected flag.
 func (f *FlagClause) addAlias(alias string, kind aliasKind) error {
+	if current, exist := f.aliases[alias]; exist {
+		if current != kind {
+			return fmt.Errorf("Alias %s already exist", alias)
+		}
+		// Already registered, we do not modify the flag (it may be shared by concurrent parses)
+		return nil
+	}
 	if f.aliases == nil {
 		f.aliases = make(map[string]aliasKind)
 	}
-	if current, exist := f.aliases[alias]; exist && current != kind {
-		return fmt.Errorf("Alias %s already exist", alias)
-	}
 	f.aliases[alias] = kind
 	return nil
 }
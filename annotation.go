@@ -0,0 +1,106 @@
+package kingpin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Annotations selects the information appended to the help of the flags and
+// arguments in the usage.
+type Annotations int
+
+const (
+	// AnnotateDefault appends the default values, such as [default: 30s].
+	AnnotateDefault Annotations = 1 << iota
+	// AnnotateChoices appends the options of the enumerable values, such as
+	// [choices: json, yaml].
+	AnnotateChoices
+	// AnnotateEnvar appends the environment variable, such as [env: APP_X],
+	// instead of ($APP_X).
+	AnnotateEnvar
+
+	// AnnotateAll appends all the annotations.
+	AnnotateAll = AnnotateDefault | AnnotateChoices | AnnotateEnvar
+)
+
+var annotationNames = map[string]Annotations{
+	"default": AnnotateDefault,
+	"choices": AnnotateChoices,
+	"env":     AnnotateEnvar,
+	"all":     AnnotateAll,
+}
+
+// Annotate sets the annotations appended to the help of the flags and
+// arguments by the built-in templates.
+func (a *Application) Annotate(annotations Annotations) *Application {
+	a.annotations = annotations
+	return a
+}
+
+// Parses a comma separated list of annotation names (default, choices, env or
+// all).
+func parseAnnotations(names string) (Annotations, error) {
+	var annotations Annotations
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		annotation, ok := annotationNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown annotation %q", name)
+		}
+		annotations |= annotation
+	}
+	return annotations, nil
+}
+
+// Appends the annotations to the help, translated with the catalog.
+func annotate(catalog MessageCatalog, help string, annotations Annotations, defaults []string, secret bool, value Value, envar string) string {
+	var notes []string
+	if annotations&AnnotateDefault != 0 && len(defaults) > 0 && !secret {
+		notes = append(notes, fmt.Sprintf(translate(catalog, "[default: %s]"), strings.Join(defaults, ", ")))
+	}
+	if e, ok := value.(Enumerable); ok && annotations&AnnotateChoices != 0 {
+		notes = append(notes, fmt.Sprintf(translate(catalog, "[choices: %s]"), strings.Join(e.Options(), ", ")))
+	}
+	if envar != "" {
+		if annotations&AnnotateEnvar != 0 {
+			notes = append(notes, fmt.Sprintf(translate(catalog, "[env: %s]"), envar))
+		} else {
+			notes = append(notes, fmt.Sprintf("($%s)", envar))
+		}
+	}
+	if len(notes) == 0 {
+		return help
+	}
+	if help == "" {
+		return strings.Join(notes, " ")
+	}
+	return help + " " + strings.Join(notes, " ")
+}
+
+// HelpWithAnnotations returns the help message followed by the selected
+// annotations. The environment variable is always mentioned.
+func (f *FlagModel) HelpWithAnnotations(annotations Annotations) string {
+	return f.annotatedHelp(annotations, nil)
+}
+
+func (f *FlagModel) annotatedHelp(annotations Annotations, catalog MessageCatalog) string {
+	if annotations == 0 {
+		return f.HelpWithEnvar()
+	}
+	return annotate(catalog, f.Help, annotations, f.Default, f.Secret, f.Value, f.Envar)
+}
+
+// HelpWithAnnotations returns the help message followed by the selected
+// annotations. The environment variable is always mentioned.
+func (a *ArgModel) HelpWithAnnotations(annotations Annotations) string {
+	return a.annotatedHelp(annotations, nil)
+}
+
+func (a *ArgModel) annotatedHelp(annotations Annotations, catalog MessageCatalog) string {
+	if annotations == 0 {
+		return a.HelpWithEnvar()
+	}
+	return annotate(catalog, a.Help, annotations, a.Default, false, a.Value, a.Envar)
+}
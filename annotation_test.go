@@ -0,0 +1,57 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newAnnotatedApp(buf *bytes.Buffer) *Application {
+	app := newTestApp().Writer(buf)
+	app.Flag("timeout", "Timeout.").Default("30s").Envar("APP_TIMEOUT").Duration()
+	app.Flag("format", "Format.").Default("json").Enum("json", "yaml", "table")
+	app.Flag("password", "Password.").Default("secret").Secret().String()
+	app.Arg("mode", "Mode.").Default("fast").Enum("fast", "slow")
+	return app
+}
+
+func TestAnnotations(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAnnotatedApp(&buf)
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "Timeout. ($APP_TIMEOUT)\n")
+	assert.NotContains(t, buf.String(), "[default:")
+
+	buf.Reset()
+	app.Annotate(AnnotateAll).Usage(nil)
+	usage := buf.String()
+	assert.Contains(t, usage, "Timeout. [default: 30s] [env: APP_TIMEOUT]\n")
+	assert.Contains(t, usage, "Format. [default: json] [choices: json, yaml, table]\n")
+	assert.Contains(t, usage, "Password.\n")
+	assert.Contains(t, usage, "Mode. [default: fast] [choices: fast, slow]\n")
+
+	buf.Reset()
+	app.Annotate(AnnotateChoices).Usage(nil)
+	assert.Contains(t, buf.String(), "Timeout. ($APP_TIMEOUT)\n")
+	assert.Contains(t, buf.String(), "Format. [choices: json, yaml, table]\n")
+}
+
+func TestAnnotationsTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAnnotatedApp(&buf).UsageTemplate(`{{.App.Flags|AnnotatedFlagsToTwoColumns (Annotations "default, env")|FormatTwoColumns}}`)
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "Timeout. [default: 30s] [env: APP_TIMEOUT]\n")
+	assert.Contains(t, buf.String(), "Format. [default: json]\n")
+
+	_, err := parseAnnotations("default,unknown")
+	assert.EqualError(t, err, `unknown annotation "unknown"`)
+}
+
+func TestEnumerable(t *testing.T) {
+	app := newTestApp()
+	app.Flag("format", "").Enum("json", "yaml")
+	e, ok := app.GetFlag("format").Model().Value.(Enumerable)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"json", "yaml"}, e.Options())
+}
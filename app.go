@@ -1,16 +1,21 @@
 package kingpin
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
 )
 
 var (
-	// ErrCommandNotSpecified is an error object returned when no command is specified.
+	// ErrCommandNotSpecified is an error object returned when no command is
+	// specified. Use errors.Is to identify it, as it is wrapped when the message
+	// is translated (see Messages).
 	ErrCommandNotSpecified = fmt.Errorf("command not specified")
 )
 
@@ -26,25 +31,49 @@ type ApplicationValidator func(*Application) error
 type Application struct {
 	cmdMixin
 	initialized bool
+	frozen      bool
+	freezeOnce  sync.Once
+	freezeErr   error
 
 	Name string
 	Help string
 
-	author         string
-	version        string
-	errorWriter    io.Writer // Destination for errors.
-	usageWriter    io.Writer // Destination for usage
-	usageTemplate  string
-	usageFuncs     template.FuncMap
-	validator      ApplicationValidator
-	terminate      func(status int) // See Terminate()
-	noInterspersed bool             // can flags be interspersed with args (or must they come first)
-	defaultEnvars  bool
-	completion     bool
-	initMode       initMode
-	allowUnmanaged bool
-
-	// Help flag. Exposed for user customisation.
+	author            string
+	version           string
+	errorWriter       io.Writer // Destination for errors.
+	usageWriter       io.Writer // Destination for usage
+	usageTemplate     string
+	usageFuncs        template.FuncMap
+	validator         ApplicationValidator
+	terminate         func(status int) // See Terminate()
+	noInterspersed    bool             // can flags be interspersed with args (or must they come first)
+	defaultEnvars     bool
+	completion        bool
+	initMode          initMode
+	allowUnmanaged    bool
+	userAliases       map[string][]string
+	fileExpansion     fileExpansion
+	prompter          Prompter
+	promptForMissing  bool
+	closeAfterActions bool
+	opened            []io.Closer // Values holding the resources set by the parses, see Close
+	parsing           int32       // Number of running parses, updated atomically (see RunShell)
+	openedMutex       sync.Mutex  // Guards opened, which is shared by the parses of a frozen application
+	theme             *Theme
+	usePager          bool
+	pagedWriter       io.Writer // Terminal of the usage while it is piped through the pager
+	helpTopics        map[string]string
+	helpTopicOrder    []string
+	annotations       Annotations
+	categories        []string
+	manSection        string
+	manDate           string
+	htmlDocsTemplate  string
+	messages          MessageCatalog
+	stdin             io.Reader // Source of the flag values given as -
+
+	// Help flag. Exposed for user customisation. It is a Bool() flag which also
+	// accepts a help topic (--help=<topic>).
 	HelpFlag *FlagClause
 	// Help command. Exposed for user customisation. May be nil.
 	HelpCommand *CmdClause
@@ -66,30 +95,35 @@ const (
 // New creates a new Kingpin application instance.
 func New(name, help string) *Application {
 	a := &Application{
-		Name:          name,
-		Help:          help,
-		errorWriter:   os.Stderr, // Left for backwards compatibility purposes.
-		usageWriter:   os.Stderr,
-		usageTemplate: DefaultUsageTemplate,
-		terminate:     os.Exit,
+		Name:             name,
+		Help:             help,
+		errorWriter:      os.Stderr, // Left for backwards compatibility purposes.
+		usageWriter:      os.Stderr,
+		stdin:            os.Stdin,
+		usageTemplate:    DefaultUsageTemplate,
+		htmlDocsTemplate: DefaultHTMLDocsTemplate,
+		terminate:        os.Exit,
 	}
 	a.flagGroup = newFlagGroup()
 	a.argGroup = newArgGroup()
 	a.cmdGroup = newCmdGroup(a)
-	a.HelpFlag = a.Flag("help", "Show context-sensitive help (also try --help-long and --help-man).")
+	a.HelpFlag = a.Flag("help", builtinFlagHelp["help"])
+	a.HelpFlag.acceptsTopic = true
 	a.HelpFlag.Bool()
-	a.Flag("help-long", "Generate long help.").Hidden().PreAction(a.generateLongHelp).Bool()
-	a.Flag("help-man", "Generate a man page.").Hidden().PreAction(a.generateManPage).Bool()
-	a.Flag("completion-bash", "Output possible completions for the given args.").Hidden().BoolVar(&a.completion)
-	a.Flag("completion-script-bash", "Generate completion script for bash.").Hidden().PreAction(a.generateBashCompletionScript).Bool()
-	a.Flag("completion-script-zsh", "Generate completion script for ZSH.").Hidden().PreAction(a.generateZSHCompletionScript).Bool()
+	a.Flag("help-long", builtinFlagHelp["help-long"]).Hidden().PreAction(a.generateLongHelp).Bool()
+	a.Flag("help-man", builtinFlagHelp["help-man"]).Hidden().PreAction(a.generateManPage).Bool()
+	a.Flag("completion-bash", builtinFlagHelp["completion-bash"]).Hidden().BoolVar(&a.completion)
+	a.Flag("completion-script-bash", builtinFlagHelp["completion-script-bash"]).Hidden().PreAction(a.generateBashCompletionScript).Bool()
+	a.Flag("completion-script-zsh", builtinFlagHelp["completion-script-zsh"]).Hidden().PreAction(a.generateZSHCompletionScript).Bool()
 
 	return a
 }
 
 func (a *Application) generateLongHelp(c *ParseContext) error {
 	a.Writer(os.Stdout)
-	if err := a.UsageForContextWithTemplate(c, 2, LongHelpTemplate); err != nil {
+	if err := a.withPager(func() error {
+		return a.UsageForContextWithTemplate(c, 2, LongHelpTemplate)
+	}); err != nil {
 		return err
 	}
 	a.terminate(0)
@@ -98,7 +132,9 @@ func (a *Application) generateLongHelp(c *ParseContext) error {
 
 func (a *Application) generateManPage(c *ParseContext) error {
 	a.Writer(os.Stdout)
-	if err := a.UsageForContextWithTemplate(c, 2, ManPageTemplate); err != nil {
+	if err := a.withPager(func() error {
+		return a.UsageForContextWithTemplate(c, 2, ManPageTemplate)
+	}); err != nil {
 		return err
 	}
 	a.terminate(0)
@@ -189,15 +225,29 @@ func (a *Application) ParseContext(args []string) (*ParseContext, error) {
 }
 
 func (a *Application) parseContext(ignoreDefault bool, args []string) (*ParseContext, error) {
+	return a.parseContextMode(ignoreDefault, a.frozen, args)
+}
+
+// Parses the command line, without modifying the application if readOnly is
+// set.
+func (a *Application) parseContextMode(ignoreDefault, readOnly bool, args []string) (*ParseContext, error) {
 	if err := a.init(); err != nil {
 		return nil, err
 	}
-	context := tokenize(args, ignoreDefault)
+	args, err := a.expandUserAliases(args)
+	if err != nil {
+		return nil, err
+	}
+	context := tokenize(args, ignoreDefault, a.fileExpansion.resolve())
 	context.flags.autoShortcut = a.autoShortcut
-	if a.allowUnmanaged {
-		context.appUnmanagedArgs = a
+	context.allowUnmanaged = a.allowUnmanaged
+	context.messages = a.messages
+	context.stdin = a.stdin
+	context.readOnly = readOnly
+	err = parse(context, a)
+	if !context.readOnly {
+		a.Unmanaged = append(a.Unmanaged, context.unmanaged...)
 	}
-	err := parse(context, a)
 	return context, err
 }
 
@@ -208,6 +258,16 @@ func (a *Application) parseContext(ignoreDefault bool, args []string) (*ParseCon
 // This will populate all flag and argument values, call all callbacks, and so
 // on.
 func (a *Application) Parse(args []string) (command string, err error) {
+	return a.ParseWithContext(context.Background(), args)
+}
+
+// ParseWithContext parses command-line arguments like Parse, but ctx is made
+// available to the actions through ParseContext.Context() (see ActionCtx).
+// The remaining actions are not executed once ctx is done, and its error is
+// returned.
+func (a *Application) ParseWithContext(ctx context.Context, args []string) (command string, err error) {
+	atomic.AddInt32(&a.parsing, 1)
+	defer atomic.AddInt32(&a.parsing, -1)
 	context, parseErr := a.ParseContext(args)
 	var selected []string
 	var setValuesErr error
@@ -217,52 +277,68 @@ func (a *Application) Parse(args []string) (command string, err error) {
 		// where a context returns nil. Protect against that.
 		return "", parseErr
 	}
+	defer func() {
+		// Only the values set by this parse are closed on error
+		if err != nil || a.closeAfterActions {
+			if closeErr := closeAll(context.opened); err == nil {
+				err = closeErr
+			}
+		} else {
+			a.addOpened(context.opened...)
+		}
+	}()
+	context.ctx = ctx
+	if context.readOnly {
+		// The application is frozen, so the values are only bound here
+		if len(context.unmanaged) > 0 {
+			a.Unmanaged = append(a.Unmanaged, context.unmanaged...)
+		}
+		for _, element := range context.Elements {
+			if flag, ok := element.Clause.(*FlagClause); ok {
+				flag.isSetByUser()
+			}
+		}
+	}
 
 	if err = a.setDefaults(context); err != nil {
-		return "", err
+		return "", translateError(a.messages, err)
 	}
 
 	selected, setValuesErr = a.setValues(context)
-
-	if err = a.applyPreActions(context, !a.completion); err != nil {
-		return "", err
+	setValuesErr = translateError(a.messages, setValuesErr)
+	if !a.completion {
+		a.warnDeprecated(context)
 	}
 
 	if a.completion {
+		if _, err = a.applyPreActions(context, false); err != nil {
+			return "", err
+		}
 		a.generateBashCompletion(context)
 		a.terminate(0)
-	} else {
-		if parseErr != nil {
-			return "", parseErr
-		}
-
-		a.maybeHelp(context)
-		if !context.EOL() {
-			return "", fmt.Errorf("unexpected argument '%s'", context.Peek())
-		}
-
-		if setValuesErr != nil {
-			return "", setValuesErr
-		}
+		return "", nil
+	}
 
-		command, err = a.execute(context, selected)
-		if err == ErrCommandNotSpecified {
-			a.writeUsage(context, nil)
-		}
+	command, err = a.execute(context, selected, parseErr, setValuesErr)
+	if err == ErrCommandNotSpecified {
+		a.writeUsage(context, nil)
 	}
-	return command, err
+	return command, translateError(a.messages, err)
 }
 
 func (a *Application) writeUsage(context *ParseContext, err error) {
+	usage := func() error { return a.UsageForContext(context) }
 	if err != nil {
 		a.Errorf("%s", err)
-	}
-	if err := a.UsageForContext(context); err != nil {
-		panic(err)
-	}
-	if err != nil {
+		if err := usage(); err != nil {
+			panic(err)
+		}
 		a.terminate(1)
 	} else {
+		// Only the requested help is paged, not the usage following an error
+		if err := a.withPager(usage); err != nil {
+			panic(err)
+		}
 		a.terminate(0)
 	}
 }
@@ -272,7 +348,11 @@ func (a *Application) maybeHelp(context *ParseContext) {
 		if flag, ok := element.Clause.(*FlagClause); ok && flag == a.HelpFlag {
 			// Re-parse the command-line ignoring defaults, so that help works correctly.
 			context, _ = a.parseContext(true, context.rawArgs)
-			a.writeUsage(context, nil)
+			if topic := helpFlagTopic(*element.Value); topic != "" {
+				a.terminate(a.writeHelpTopic(context, topic))
+			} else {
+				a.writeUsage(context, nil)
+			}
 		}
 	}
 }
@@ -280,7 +360,7 @@ func (a *Application) maybeHelp(context *ParseContext) {
 // Version adds a --version flag for displaying the application version.
 func (a *Application) Version(version string) *Application {
 	a.version = version
-	a.VersionFlag = a.Flag("version", "Show application version.").PreAction(func(*ParseContext) error {
+	a.VersionFlag = a.Flag("version", builtinFlagHelp["version"]).PreAction(func(*ParseContext) error {
 		fmt.Fprintln(a.usageWriter, version)
 		a.terminate(0)
 		return nil
@@ -317,6 +397,33 @@ func (a *Application) PreAction(action Action) *Application {
 	return a
 }
 
+// ActionCtx is like Action, but the callback also receives the context supplied
+// to Application.ParseWithContext.
+func (a *Application) ActionCtx(action ActionCtx) *Application {
+	return a.Action(WithContext(action))
+}
+
+// PreActionCtx is like PreAction, but the callback also receives the context
+// supplied to Application.ParseWithContext.
+func (a *Application) PreActionCtx(action ActionCtx) *Application {
+	return a.PreAction(WithContext(action))
+}
+
+// PostAction called after the pre-actions, the validation and the actions of
+// the application and the selected commands, flags and arguments, even if
+// they failed. The application post actions are executed last.
+func (a *Application) PostAction(action Action) *Application {
+	a.addPostAction(action)
+	return a
+}
+
+// OnError called with any error returned by the pre-actions, the validation or
+// the actions, before the post actions.
+func (a *Application) OnError(action ErrorAction) *Application {
+	a.addErrorAction(action)
+	return a
+}
+
 // Command adds a new top-level command.
 func (a *Application) Command(name, help string) *CmdClause {
 	return a.addCommand(name, help)
@@ -339,6 +446,47 @@ func (a *Application) InitOnlyOnce() *Application {
 // ResetInitOnlyOnce instructs the Parser to reevaluate the default values on the next parsing.
 func (a *Application) ResetInitOnlyOnce() { a.initMode = initDisabledOnMultipleParse }
 
+// Reset restores the targets of all flags and arguments to the value they had
+// when they were bound (or to their zero value for the custom values, see
+// Resettable), clears the unmanaged arguments and re-enables the
+// initialization of the default values disabled by InitOnlyOnce. The
+// application can then be parsed again as if it had never been.
+func (a *Application) Reset() {
+	a.walkClauses(func(flag *FlagClause) {
+		flag.parserMixin.reset()
+		if flag.setByUser != nil {
+			*flag.setByUser = false
+		}
+	}, func(arg *ArgClause) {
+		arg.parserMixin.reset()
+	})
+	// The resources have been closed by the values
+	a.openedMutex.Lock()
+	a.opened = nil
+	a.openedMutex.Unlock()
+	a.Unmanaged = nil
+	if a.initMode == initDisabled {
+		a.initMode = initDisabledOnMultipleParse
+	}
+}
+
+// Calls the functions for all flags and arguments of the application and its commands.
+func (a *Application) walkClauses(flagFn func(*FlagClause), argFn func(*ArgClause)) {
+	var walk func(flags *flagGroup, args *argGroup, cmds *cmdGroup)
+	walk = func(flags *flagGroup, args *argGroup, cmds *cmdGroup) {
+		for _, flag := range flags.flagOrder {
+			flagFn(flag)
+		}
+		for _, arg := range args.args {
+			argFn(arg)
+		}
+		for _, cmd := range cmds.commandOrder {
+			walk(cmd.flagGroup, cmd.argGroup, cmd.cmdGroup)
+		}
+	}
+	walk(a.flagGroup, a.argGroup, a.cmdGroup)
+}
+
 // AutoShortcut enables automatic creation of aliases based on the first letter of each long-options.
 func (a *Application) AutoShortcut() *Application {
 	a.autoShortcut = true
@@ -369,12 +517,11 @@ func (a *Application) init() error {
 	// If we have subcommands, add a help command at the top-level.
 	if a.cmdGroup.have() {
 		var command []string
-		a.HelpCommand = a.Command("help", "Show help.").PreAction(func(context *ParseContext) error {
-			a.Usage(command)
-			a.terminate(0)
+		a.HelpCommand = a.Command("help", a.translate("Show help.")).PreAction(func(context *ParseContext) error {
+			a.terminate(a.helpCommand(command))
 			return nil
 		})
-		a.HelpCommand.Arg("command", "Show help on command.").StringsVar(&command)
+		a.HelpCommand.Arg("command", a.translate("Show help on command.")).StringsVar(&command)
 		// Make help first command.
 		l := len(a.commandOrder)
 		a.commandOrder = append(a.commandOrder[l-1:l], a.commandOrder[:l-1]...)
@@ -386,6 +533,12 @@ func (a *Application) init() error {
 	if err := a.cmdGroup.init(); err != nil {
 		return err
 	}
+	if err := checkReplacements(nil, a.flagGroup, a.cmdGroup); err != nil {
+		return err
+	}
+	if err := a.checkUserAliases(); err != nil {
+		return err
+	}
 	if err := a.argGroup.init(); err != nil {
 		return err
 	}
@@ -400,6 +553,9 @@ func (a *Application) init() error {
 			return err
 		}
 	}
+	if err := a.checkExamples(); err != nil {
+		return err
+	}
 	a.initialized = true
 	return nil
 }
@@ -429,26 +585,56 @@ func checkDuplicateFlags(current *CmdClause, flagGroups []*flagGroup) error {
 	return nil
 }
 
-func (a *Application) execute(context *ParseContext, selected []string) (string, error) {
-	var err error
+// Executes the pre-actions, the validation and the actions. The post actions
+// of the clauses whose pre-actions ran are executed in any case, and their
+// error actions receive the error of the failed clause or of a selected
+// command (see applyPostActions).
+func (a *Application) execute(context *ParseContext, selected []string, parseErr, setValuesErr error) (command string, err error) {
+	started := 0           // Number of elements whose pre-actions ran
+	var failed interface{} // Clause whose pre-action, action or validator failed
+	defer func() {
+		if err = a.applyPostActions(context, started, failed, err); err != nil {
+			command = ""
+		}
+	}()
+
+	if started, err = a.applyPreActions(context, true); err != nil {
+		if started > 0 {
+			failed = context.Elements[started-1].Clause
+		}
+		return "", err
+	}
+
+	if parseErr != nil {
+		return "", parseErr
+	}
+
+	a.maybeHelp(context)
+	if !context.EOL() {
+		return "", context.errorf("unexpected argument '%s'", context.Peek())
+	}
+
+	if setValuesErr != nil {
+		return "", setValuesErr
+	}
 
 	if err = a.validateRequired(context); err != nil {
 		return "", err
 	}
 
-	if err = a.applyValidators(context); err != nil {
+	if failed, err = a.applyValidators(context); err != nil {
 		return "", err
 	}
 
-	if err = a.applyActions(context); err != nil {
+	if failed, err = a.applyActions(context); err != nil {
 		return "", err
 	}
 
-	command := strings.Join(selected, " ")
+	command = strings.Join(selected, " ")
 	if command == "" && a.cmdGroup.have() {
 		return "", ErrCommandNotSpecified
 	}
-	return command, err
+	return command, nil
 }
 
 func (a *Application) setDefaults(context *ParseContext) error {
@@ -463,6 +649,10 @@ func (a *Application) setDefaults(context *ParseContext) error {
 				return nil
 			}
 			flagElements[flag.name] = element
+			flagElements[flag.target().name] = element
+			if flag.replacedBy != "" {
+				flagElements[flag.replacedBy] = element
+			}
 		}
 	}
 
@@ -477,16 +667,20 @@ func (a *Application) setDefaults(context *ParseContext) error {
 		// Check required flags and set defaults.
 		for _, flag := range context.flags.long {
 			if flagElements[flag.name] == nil {
-				if err := flag.setDefault(); err != nil {
+				if err := flag.setDefault(context.stdin); err != nil {
 					return err
 				}
+				if flag.HasEnvarValue() || len(flag.defaultValues) > 0 {
+					context.trackCloser(flag.target().value)
+				}
 			} else if v, ok := flag.value.(repeatableFlag); ok && v.IsCumulative() && flag.HasEnvarValue() {
 				// In the case of a repeatable flag, we join the environment variables to the provided values
 				for _, value := range flag.GetSplitEnvarValue() {
-					if err := flag.value.Set(value); err != nil {
+					if err := flag.setValue(value); err != nil {
 						return err
 					}
 				}
+				context.trackCloser(flag.value)
 				return nil
 			}
 		}
@@ -496,6 +690,9 @@ func (a *Application) setDefaults(context *ParseContext) error {
 				if err := arg.setDefault(); err != nil {
 					return err
 				}
+				if arg.HasEnvarValue() || len(arg.defaultValues) > 0 {
+					context.trackCloser(arg.value)
+				}
 			}
 		}
 	}
@@ -509,10 +706,24 @@ func (a *Application) setDefaults(context *ParseContext) error {
 }
 
 func (a *Application) validateRequired(context *ParseContext) error {
+	if prompter := a.activePrompter(); prompter != nil {
+		if err := a.promptMissing(context, prompter); err != nil {
+			return err
+		}
+	}
+	return checkRequired(context)
+}
+
+// Checks that the required flags and arguments are supplied by the context.
+func checkRequired(context *ParseContext) error {
 	flagElements := map[string]*ParseElement{}
 	for _, element := range context.Elements {
 		if flag, ok := element.Clause.(*FlagClause); ok {
 			flagElements[flag.name] = element
+			flagElements[flag.target().name] = element
+			if flag.replacedBy != "" {
+				flagElements[flag.replacedBy] = element
+			}
 		}
 	}
 
@@ -534,13 +745,13 @@ func (a *Application) validateRequired(context *ParseContext) error {
 		}
 	}
 	if len(missingFlags) != 0 {
-		return fmt.Errorf("required flag(s) %s not provided", strings.Join(missingFlags, ", "))
+		return context.errorf("required flag(s) %s not provided", strings.Join(missingFlags, ", "))
 	}
 
 	for _, arg := range context.arguments.args {
 		if argElements[arg.name] == nil {
 			if arg.needsValue() {
-				return fmt.Errorf("required argument '%s' not provided", arg.name)
+				return context.errorf("required argument '%s' not provided", arg.name)
 			}
 		}
 	}
@@ -558,18 +769,36 @@ func (a *Application) setValues(context *ParseContext) (selected []string, err e
 		case *FlagClause:
 			if _, ok := flagSet[clause.name]; ok {
 				if v, ok := clause.value.(repeatableFlag); !ok || !v.IsCumulative() {
-					return nil, fmt.Errorf("flag '%s' cannot be repeated", clause.name)
+					return nil, context.errorf("flag '%s' cannot be repeated", clause.name)
 				}
 			}
-			if err = clause.value.Set(*element.Value); err != nil {
+			value := clause.valueWithoutTopic(*element.Value)
+			if file, ok := clause.value.(*secretFileValue); ok {
+				err = file.load(value, context.stdin)
+			} else if value, err = clause.resolveFileValue(value, context.stdin); err == nil {
+				err = clause.setValue(value)
+			}
+			if err != nil {
 				return
 			}
+			context.trackCloser(clause.target().value)
+			if replacement := clause.replacement(context); replacement != nil {
+				if err = replacement.setValue(value); err != nil {
+					return
+				}
+				context.trackCloser(replacement.value)
+			}
+			if clause.secret {
+				// Do not keep a reference to the secret value
+				*element.Value = ""
+			}
 			flagSet[clause.name] = struct{}{}
 
 		case *ArgClause:
 			if err = clause.value.Set(*element.Value); err != nil {
 				return
 			}
+			context.trackCloser(clause.value)
 
 		case *CmdClause:
 			selected = append(selected, clause.name)
@@ -578,64 +807,96 @@ func (a *Application) setValues(context *ParseContext) (selected []string, err e
 	}
 
 	if lastCmd != nil && len(lastCmd.commands) > 0 {
-		return nil, fmt.Errorf("must select a subcommand of '%s'", lastCmd.FullCommand())
+		return nil, context.errorf("must select a subcommand of '%s'", lastCmd.FullCommand())
 	}
 
 	return
 }
 
-func (a *Application) applyValidators(context *ParseContext) (err error) {
+// The validators, pre-actions and actions return the clause which failed, if
+// any, along with the error.
+func (a *Application) applyValidators(context *ParseContext) (interface{}, error) {
 	// Call command validation functions.
 	for _, element := range context.Elements {
 		if cmd, ok := element.Clause.(*CmdClause); ok && cmd.validator != nil {
-			if err = cmd.validator(cmd); err != nil {
-				return err
+			if err := cmd.validator(cmd); err != nil {
+				return cmd, err
 			}
 		}
 	}
 
 	if a.validator != nil {
-		err = a.validator(a)
+		return nil, a.validator(a)
 	}
-	return err
+	return nil, nil
 }
 
-func (a *Application) applyPreActions(context *ParseContext, dispatch bool) error {
+// Applies the pre-actions of the application, then of the elements if dispatch
+// is set. The number of elements whose pre-actions ran is returned, including
+// the element whose pre-action failed.
+func (a *Application) applyPreActions(context *ParseContext, dispatch bool) (int, error) {
 	if err := a.actionMixin.applyPreActions(context); err != nil {
-		return err
+		return 0, err
+	}
+	if !dispatch {
+		return 0, nil
 	}
 	// Dispatch to actions.
-	if dispatch {
-		for _, element := range context.Elements {
-			if applier, ok := element.Clause.(actionApplier); ok {
-				if err := applier.applyPreActions(context); err != nil {
-					return err
-				}
+	for i, element := range context.Elements {
+		if applier, ok := element.Clause.(actionApplier); ok {
+			if err := applier.applyPreActions(context); err != nil {
+				return i + 1, err
 			}
 		}
 	}
-
-	return nil
+	return len(context.Elements), nil
 }
 
-func (a *Application) applyActions(context *ParseContext) error {
+func (a *Application) applyActions(context *ParseContext) (interface{}, error) {
 	if err := a.actionMixin.applyActions(context); err != nil {
-		return err
+		return nil, err
 	}
 	// Dispatch to actions.
 	for _, element := range context.Elements {
 		if applier, ok := element.Clause.(actionApplier); ok {
 			if err := applier.applyActions(context); err != nil {
-				return err
+				return element.Clause, err
 			}
 		}
 	}
-	return nil
+	return nil, nil
+}
+
+// Applies the post actions of the first started elements in reverse order,
+// then those of the application, even if err is not nil. The error actions of
+// the failed clause, of the selected commands and of the application receive
+// err. err is returned if not nil, else the first error of a post action.
+func (a *Application) applyPostActions(context *ParseContext, started int, failed interface{}, err error) error {
+	var postErr error
+	for i := started - 1; i >= 0; i-- {
+		clause := context.Elements[i].Clause
+		if applier, ok := clause.(actionApplier); ok {
+			var clauseErr error
+			if _, isCmd := clause.(*CmdClause); isCmd || clause == failed {
+				clauseErr = err
+			}
+			if e := applier.applyPostActions(context, clauseErr); postErr == nil {
+				postErr = e
+			}
+		}
+	}
+	if e := a.actionMixin.applyPostActions(context, err); postErr == nil {
+		postErr = e
+	}
+	if err != nil {
+		return err
+	}
+	return postErr
 }
 
 // Errorf prints an error message to w in the format "<appname>: error: <message>".
 func (a *Application) Errorf(format string, args ...interface{}) {
-	fmt.Fprintf(a.errorWriter, a.Name+": error: "+format+"\n", args...)
+	fmt.Fprintf(a.errorWriter, a.Name+": "+a.translate("error")+": "+format+"\n", args...)
 }
 
 // Fatalf writes a formatted error to w then terminates with exit status 1.
@@ -760,3 +760,59 @@ func TestVersion(t *testing.T) {
 	_, err := c.Parse([]string{"--version"})
 	assert.NoError(t, err)
 }
+
+func TestReset(t *testing.T) {
+	var set bool
+	app := newTestApp().AllowUnmanaged().InitOnlyOnce()
+	tags := app.Flag("tag", "").IsSetByUser(&set).Strings()
+	labels := app.Flag("label", "").StringMap()
+	verbose := app.Flag("verbose", "").Short('v').Counter()
+	name := app.Flag("name", "").Default("default").String()
+
+	args := []string{"--tag=a", "--label=k=v", "-vv", "--unknown"}
+	_, err := app.Parse(args)
+	assert.NoError(t, err)
+	_, err = app.Parse(args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "a"}, *tags)
+	assert.Equal(t, 4, *verbose)
+	assert.Equal(t, []string{"--unknown", "--unknown"}, app.Unmanaged)
+
+	app.Reset()
+	assert.Empty(t, *tags)
+	assert.Empty(t, *labels)
+	assert.Equal(t, 0, *verbose)
+	assert.Equal(t, "", *name)
+	assert.False(t, set)
+	assert.Empty(t, app.Unmanaged)
+
+	_, err = app.Parse(args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, *tags)
+	assert.Equal(t, map[string]string{"k": "v"}, *labels)
+	assert.Equal(t, 2, *verbose)
+	assert.Equal(t, "default", *name)
+	assert.True(t, set)
+	assert.Equal(t, []string{"--unknown"}, app.Unmanaged)
+}
+
+func TestResetRestoresInitialTargets(t *testing.T) {
+	app := newTestApp()
+	name := "preset"
+	tags := []string{"x"}
+	labels := map[string]string{"k": "preset"}
+	app.Flag("name", "").StringVar(&name)
+	app.Flag("tag", "").StringsVar(&tags)
+	app.Flag("label", "").StringMapVar(&labels)
+
+	_, err := app.Parse([]string{"--name=parsed", "--tag=y", "--label=k=parsed", "--label=l=parsed"})
+	assert.NoError(t, err)
+	assert.Equal(t, "parsed", name)
+	assert.Equal(t, []string{"x", "y"}, tags)
+	assert.Equal(t, map[string]string{"k": "parsed", "l": "parsed"}, labels)
+
+	app.Reset()
+	assert.Equal(t, "preset", name)
+	assert.Equal(t, []string{"x"}, tags)
+	assert.Equal(t, map[string]string{"k": "preset"}, labels)
+}
@@ -69,8 +69,10 @@ type ArgClause struct {
 	parserMixin
 	completionsMixin
 	envarMixin
+	deprecationMixin
 	name          string
 	help          string
+	helpLong      string
 	defaultValues []string
 	placeholder   string
 	hidden        bool
@@ -183,6 +185,34 @@ func (a *ArgClause) PreAction(action Action) *ArgClause {
 	return a
 }
 
+// ActionCtx is like Action, but the callback also receives the context supplied
+// to Application.ParseWithContext.
+func (a *ArgClause) ActionCtx(action ActionCtx) *ArgClause {
+	return a.Action(WithContext(action))
+}
+
+// PreActionCtx is like PreAction, but the callback also receives the context
+// supplied to Application.ParseWithContext.
+func (a *ArgClause) PreActionCtx(action ActionCtx) *ArgClause {
+	return a.PreAction(WithContext(action))
+}
+
+// PostAction called after the actions, even if they failed, once the
+// pre-actions of the clause ran. Post actions are executed in the reverse
+// order of the command line, and in the reverse order of their registration
+// for a given clause.
+func (a *ArgClause) PostAction(action Action) *ArgClause {
+	a.addPostAction(action)
+	return a
+}
+
+// OnError called with the error returned by a pre-action or an action of the
+// argument, before the post actions.
+func (a *ArgClause) OnError(action ErrorAction) *ArgClause {
+	a.addErrorAction(action)
+	return a
+}
+
 // HintAction registers a HintAction (function) for the arg to provide completions
 func (a *ArgClause) HintAction(action HintAction) *ArgClause {
 	a.addHintAction(action)
@@ -203,6 +233,13 @@ func (a *ArgClause) Help(help string) *ArgClause {
 	return a
 }
 
+// HelpLong adds a long help text, displayed by the long help and the man page
+// in addition to the help message.
+func (a *ArgClause) HelpLong(help string) *ArgClause {
+	a.helpLong = help
+	return a
+}
+
 func (a *ArgClause) init() error {
 	if a.required && len(a.defaultValues) > 0 {
 		return fmt.Errorf("required argument '%s' with unusable default value", a.name)
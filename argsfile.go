@@ -0,0 +1,352 @@
+package kingpin
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ArgsFileSyntax determines how the content of a @file argument is split
+// into arguments.
+type ArgsFileSyntax int
+
+const (
+	// ArgsFileLines treats each line of the file as a single argument. Empty
+	// lines and lines starting with # are ignored. This is the default.
+	ArgsFileLines ArgsFileSyntax = iota
+	// ArgsFileShell splits the file content like a shell would: arguments are
+	// separated by blanks and can be quoted with ' or ", \ escapes the next
+	// character or continues the line and # starts a comment. $VAR and
+	// ${VAR} are replaced by the environment variable value (except within
+	// single quotes) and unquoted @file arguments are recursively expanded
+	// (relative paths are resolved from the including file directory), using
+	// the prefix and the expander of the application.
+	ArgsFileShell
+)
+
+// FileExpander returns the arguments read from the named file of fsys. If
+// fsys is nil, the file must be read from the operating system.
+type FileExpander func(fsys fs.FS, name string) ([]string, error)
+
+// Configuration of the @file arguments expansion.
+type fileExpansion struct {
+	enabled  *bool // Defaults to EnableFileExpansion if not set
+	prefix   string
+	syntax   ArgsFileSyntax
+	fsys     fs.FS
+	expander FileExpander
+}
+
+// FileExpansion enables or disables the expansion of @file arguments for this
+// application. Defaults to the value of the global EnableFileExpansion.
+func (a *Application) FileExpansion(enabled bool) *Application {
+	a.fileExpansion.enabled = &enabled
+	return a
+}
+
+// FileExpansionPrefix sets the character identifying the arguments that must
+// be expanded from a file. Defaults to '@'.
+func (a *Application) FileExpansionPrefix(prefix rune) *Application {
+	a.fileExpansion.prefix = string(prefix)
+	return a
+}
+
+// FileExpansionSyntax sets the syntax used to read arguments from @file.
+func (a *Application) FileExpansionSyntax(syntax ArgsFileSyntax) *Application {
+	a.fileExpansion.syntax = syntax
+	return a
+}
+
+// FileExpansionFS sets the file system from which the @file arguments are
+// read. Defaults to the operating system file system.
+func (a *Application) FileExpansionFS(fsys fs.FS) *Application {
+	a.fileExpansion.fsys = fsys
+	return a
+}
+
+// FileExpander sets a custom function to expand the @file arguments. It
+// receives the file system set by FileExpansionFS() (which may be nil) and
+// the file name without its prefix. With ArgsFileShell, the returned
+// arguments starting with the prefix are expanded with it too.
+func (a *Application) FileExpander(expander FileExpander) *Application {
+	a.fileExpansion.expander = expander
+	return a
+}
+
+// Returns the configuration to use for a parse, resolving the defaults.
+func (f fileExpansion) resolve() *fileExpansion {
+	if f.enabled == nil {
+		enabled := EnableFileExpansion
+		f.enabled = &enabled
+	}
+	if f.prefix == "" {
+		f.prefix = "@"
+	}
+	return &f
+}
+
+// Returns the file name if the argument must be expanded.
+func (f *fileExpansion) fileName(arg string) (string, bool) {
+	if !*f.enabled || !strings.HasPrefix(arg, f.prefix) {
+		return "", false
+	}
+	return arg[len(f.prefix):], true
+}
+
+func (f *fileExpansion) expand(name string) ([]string, error) {
+	return f.expandIncluded(name, nil)
+}
+
+// Expands the named file, included by the includedBy files with the shell
+// syntax.
+func (f *fileExpansion) expandIncluded(name string, includedBy []string) ([]string, error) {
+	if f.expander == nil {
+		if f.syntax == ArgsFileShell {
+			return expandShellArgsFile(f, name, includedBy)
+		}
+		return ExpandArgsFromFS(f.fsys, name, f.syntax)
+	}
+	args, err := f.expander(f.fsys, name)
+	if err != nil || f.syntax != ArgsFileShell {
+		return args, err
+	}
+	for _, parent := range includedBy {
+		if parent == name {
+			return nil, fmt.Errorf("recursive inclusion of arguments file %q", name)
+		}
+	}
+	var out []string
+	for _, arg := range args {
+		included, ok := f.fileName(arg)
+		if !ok {
+			out = append(out, arg)
+			continue
+		}
+		expanded, err := f.expandIncluded(included, append(includedBy, name))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, expanded...)
+	}
+	return out, nil
+}
+
+// Default configuration of the expansion functions not bound to an
+// application.
+func defaultFileExpansion(fsys fs.FS) *fileExpansion {
+	enabled := true
+	return &fileExpansion{enabled: &enabled, prefix: "@", syntax: ArgsFileShell, fsys: fsys}
+}
+
+// ExpandArgsFromFS expands arguments from the named file of fsys according to
+// the syntax. If fsys is nil, the file is read from the operating system.
+func ExpandArgsFromFS(fsys fs.FS, name string, syntax ArgsFileSyntax) ([]string, error) {
+	if syntax == ArgsFileShell {
+		return expandShellArgsFile(defaultFileExpansion(fsys), name, nil)
+	}
+	if fsys == nil {
+		return ExpandArgsFromFile(name)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("expected @ file to expand arguments from")
+	}
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open arguments file %q: %s", name, err)
+	}
+	return splitArgsLines(bytes.NewReader(content))
+}
+
+// ExpandShellArgsFromFile expands arguments from a file using the shell
+// syntax (see ArgsFileShell).
+func ExpandShellArgsFromFile(filename string) ([]string, error) {
+	return expandShellArgsFile(defaultFileExpansion(nil), filename, nil)
+}
+
+// Expands a file with the shell syntax, included by the includedBy files. The
+// included files are expanded with the prefix and the expander of f.
+func expandShellArgsFile(f *fileExpansion, filename string, includedBy []string) (out []string, err error) {
+	fsys := f.fsys
+	if filename == "" {
+		return nil, fmt.Errorf("expected @ file to expand arguments from")
+	}
+	var content []byte
+	if fsys == nil {
+		if len(includedBy) > 0 && !filepath.IsAbs(filename) {
+			filename = filepath.Join(filepath.Dir(includedBy[len(includedBy)-1]), filename)
+		}
+		if abs, err := filepath.Abs(filename); err == nil {
+			filename = abs
+		}
+	} else if len(includedBy) > 0 && !path.IsAbs(filename) {
+		filename = path.Join(path.Dir(includedBy[len(includedBy)-1]), filename)
+	}
+	for _, parent := range includedBy {
+		if parent == filename {
+			return nil, fmt.Errorf("recursive inclusion of arguments file %q", filename)
+		}
+	}
+
+	if fsys == nil {
+		content, err = os.ReadFile(filename)
+	} else {
+		content, err = fs.ReadFile(fsys, filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open arguments file %q: %s", filename, err)
+	}
+	words, err := splitShellWords(string(content), f.prefix, os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read arguments from %q: %s", filename, err)
+	}
+	for _, word := range words {
+		if !word.include {
+			out = append(out, word.value)
+			continue
+		}
+		included, err := f.expandIncluded(word.value[len(f.prefix):], append(includedBy, filename))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, included...)
+	}
+	return out, nil
+}
+
+type shellWord struct {
+	value   string
+	include bool // The word starts with an unquoted prefix
+}
+
+// Splits the content into words using a shell like syntax. The words starting
+// with an unquoted prefix are marked as includes.
+func splitShellWords(content, prefix string, getenv func(string) string) ([]shellWord, error) {
+	var (
+		words   []shellWord
+		current strings.Builder
+		inWord  bool
+		include bool
+		line    = 1
+		runes   = []rune(content)
+	)
+
+	endWord := func() {
+		if inWord {
+			words = append(words, shellWord{current.String(), include})
+		}
+		current.Reset()
+		inWord, include = false, false
+	}
+
+	// Expands the variable starting at position i ($ excluded) and returns the
+	// position of the last consumed rune.
+	expandVariable := func(i int) int {
+		if i < len(runes) && runes[i] == '{' {
+			if end := indexRune(runes, i+1, '}'); end > 0 {
+				current.WriteString(getenv(string(runes[i+1 : end])))
+				return end
+			}
+		}
+		start := i
+		for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || i > start && unicode.IsDigit(runes[i])) {
+			i++
+		}
+		if i == start {
+			current.WriteRune('$')
+		} else {
+			current.WriteString(getenv(string(runes[start:i])))
+		}
+		return i - 1
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\n':
+			line++
+			endWord()
+		case r == ' ' || r == '\t' || r == '\r':
+			endWord()
+		case r == '#' && !inWord:
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("line %d: unexpected end of file after \\", line)
+			}
+			i++
+			if runes[i] == '\n' {
+				// Line continuation
+				line++
+				continue
+			}
+			current.WriteRune(runes[i])
+			inWord = true
+		case r == '\'':
+			inWord = true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", line)
+			}
+			quoted := string(runes[i+1 : end])
+			line += strings.Count(quoted, "\n")
+			current.WriteString(quoted)
+			i = end
+		case r == '"':
+			inWord = true
+			start := line
+			for i++; ; i++ {
+				if i == len(runes) {
+					return nil, fmt.Errorf("line %d: unterminated double quote", start)
+				}
+				if runes[i] == '"' {
+					break
+				}
+				switch runes[i] {
+				case '\\':
+					if i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+						i++
+						if runes[i] == '\n' {
+							line++
+							continue
+						}
+					}
+					current.WriteRune(runes[i])
+				case '$':
+					i = expandVariable(i + 1)
+				default:
+					if runes[i] == '\n' {
+						line++
+					}
+					current.WriteRune(runes[i])
+				}
+			}
+		case r == '$':
+			inWord = true
+			i = expandVariable(i + 1)
+		default:
+			if !inWord && strings.HasPrefix(string(runes[i:]), prefix) {
+				include = true
+			}
+			inWord = true
+			current.WriteRune(r)
+		}
+	}
+	endWord()
+	return words, nil
+}
+
+// Returns the index of the first occurrence of r in runes starting at start.
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,217 @@
+package kingpin
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	env := map[string]string{"USER": "joe", "HOME": "/home/joe"}
+	getenv := func(name string) string { return env[name] }
+
+	cases := []struct {
+		name     string
+		content  string
+		expected []string
+		err      string
+	}{
+		{"Simple", "--name John --verbose", []string{"--name", "John", "--verbose"}, ""},
+		{"Multiple lines", "a b\nc\n\n  d  ", []string{"a", "b", "c", "d"}, ""},
+		{"Double quotes", `--name "John Smith" --verbose`, []string{"--name", "John Smith", "--verbose"}, ""},
+		{"Single quotes", `--name='John "J" Smith'`, []string{`--name=John "J" Smith`}, ""},
+		{"Empty quotes", `a "" ''`, []string{"a", "", ""}, ""},
+		{"Escapes", `a\ b \"c\" "d\"e\\f" 'g\h'`, []string{"a b", `"c"`, `d"e\f`, `g\h`}, ""},
+		{"Continuation", "--name \\\nJohn \"multi\\\nline\"", []string{"--name", "John", "multiline"}, ""},
+		{"Comments", "# comment\na # inline comment\nb#not-a-comment", []string{"a", "b#not-a-comment"}, ""},
+		{"Variables", `$USER ${HOME}/bin "$USER@host" '$USER' $UNDEFINED. $ $1`, []string{"joe", "/home/joe/bin", "joe@host", "$USER", ".", "$", "$1"}, ""},
+		{"Unterminated double quote", "a\n\"b", nil, `line 2: unterminated double quote`},
+		{"Unterminated single quote", "a 'b", nil, `line 1: unterminated single quote`},
+		{"Trailing escape", `a\`, nil, `line 1: unexpected end of file after \`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			words, err := splitShellWords(c.content, "@", getenv)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			var values []string
+			for _, word := range words {
+				values = append(values, word.value)
+			}
+			assert.Equal(t, c.expected, values)
+		})
+	}
+}
+
+func TestSplitShellWordsIncludes(t *testing.T) {
+	words, err := splitShellWords(`@file '@quoted' \@escaped a@b`, "@", os.Getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, []shellWord{{"@file", true}, {"@quoted", false}, {"@escaped", false}, {"a@b", false}}, words)
+
+	words, err = splitShellWords(`+file @file '+quoted'`, "+", os.Getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, []shellWord{{"+file", true}, {"@file", false}, {"+quoted", false}}, words)
+}
+
+func TestParserExpandShellFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0700))
+	writeFile := func(name, content string) string {
+		name = filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(name, []byte(content), 0600))
+		return name
+	}
+	main := writeFile("main", "--name \"John Smith\" # The name\n@sub/included\n")
+	writeFile("sub/included", "--verbose @other")
+	writeFile("sub/other", "'first arg'")
+
+	app := newTestApp().FileExpansionSyntax(ArgsFileShell)
+	name := app.Flag("name", "").String()
+	verbose := app.Flag("verbose", "").Bool()
+	arg := app.Arg("arg", "").String()
+	_, err := app.Parse([]string{"@" + main})
+	assert.NoError(t, err)
+	assert.Equal(t, "John Smith", *name)
+	assert.True(t, *verbose)
+	assert.Equal(t, "first arg", *arg)
+
+	cycle := writeFile("cycle", "@sub/cycle")
+	writeFile("sub/cycle", "@../cycle")
+	_, err = app.Parse([]string{"@" + cycle})
+	assert.EqualError(t, err, "recursive inclusion of arguments file "+`"`+cycle+`"`)
+
+	invalid := writeFile("invalid", "'unterminated")
+	_, err = app.Parse([]string{"@" + invalid})
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasSuffix(err.Error(), "line 1: unterminated single quote"))
+	}
+}
+
+func TestParserExpandFileDefaultSyntax(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "args")
+	assert.NoError(t, os.WriteFile(filename, []byte("--name=John Smith\n"), 0600))
+
+	app := newTestApp()
+	name := app.Flag("name", "").String()
+	_, err := app.Parse([]string{"@" + filename})
+	assert.NoError(t, err)
+	assert.Equal(t, "John Smith", *name)
+}
+
+func TestFileExpansionConfiguration(t *testing.T) {
+	fsys := fstest.MapFS{
+		"args":           {Data: []byte("--name=from-fs\n")},
+		"shell/args":     {Data: []byte("--name 'from shell' @included")},
+		"shell/other":    {Data: []byte("value")},
+		"shell/included": {Data: []byte("--other @other")},
+	}
+	newApp := func() (*Application, *string, *string) {
+		app := newTestApp()
+		name := app.Flag("name", "").String()
+		other := app.Flag("other", "").String()
+		return app, name, other
+	}
+
+	app, name, _ := newApp()
+	app.FileExpansionFS(fsys)
+	_, err := app.Parse([]string{"@args"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from-fs", *name)
+
+	app, name, other := newApp()
+	app.FileExpansionFS(fsys).FileExpansionSyntax(ArgsFileShell)
+	_, err = app.Parse([]string{"@shell/args"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from shell", *name)
+	assert.Equal(t, "value", *other)
+
+	app, name, _ = newApp()
+	app.FileExpansionFS(fsys).FileExpansionPrefix('+')
+	_, err = app.Parse([]string{"+args"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from-fs", *name)
+
+	disabled, _, _ := newApp()
+	disabled.FileExpansion(false).Arg("arg", "").String()
+	_, err = disabled.Parse([]string{"@args"})
+	assert.NoError(t, err)
+
+	app, name, _ = newApp()
+	app.FileExpander(func(f fs.FS, filename string) ([]string, error) {
+		assert.Nil(t, f)
+		return []string{"--name=" + strings.ToUpper(filename)}, nil
+	})
+	_, err = app.Parse([]string{"@custom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CUSTOM", *name)
+}
+
+func TestFileExpansionNestedIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"args":      {Data: []byte("--name=outer +sub/inner @literal")},
+		"sub/inner": {Data: []byte("--other=inner")},
+		"loop":      {Data: []byte("+loop")},
+	}
+	newApp := func() (*Application, *string, *string, *[]string) {
+		app := newTestApp().FileExpansionFS(fsys).FileExpansionPrefix('+').FileExpansionSyntax(ArgsFileShell)
+		name := app.Flag("name", "").String()
+		other := app.Flag("other", "").String()
+		args := app.Arg("args", "").Strings()
+		return app, name, other, args
+	}
+
+	app, name, other, args := newApp()
+	_, err := app.Parse([]string{"+args"})
+	assert.NoError(t, err)
+	assert.Equal(t, "outer", *name)
+	assert.Equal(t, "inner", *other)
+	assert.Equal(t, []string{"@literal"}, *args)
+
+	app, _, _, _ = newApp()
+	_, err = app.Parse([]string{"+loop"})
+	assert.EqualError(t, err, `recursive inclusion of arguments file "loop"`)
+
+	app, name, other, args = newApp()
+	app.FileExpander(func(f fs.FS, filename string) ([]string, error) {
+		if filename == "args" {
+			return []string{"--name=custom", "+inner", "@literal"}, nil
+		}
+		return []string{"--other=" + strings.ToUpper(filename)}, nil
+	})
+	_, err = app.Parse([]string{"+args"})
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", *name)
+	assert.Equal(t, "INNER", *other)
+	assert.Equal(t, []string{"@literal"}, *args)
+
+	app, _, _, _ = newApp()
+	app.FileExpander(func(fs.FS, string) ([]string, error) { return []string{"+loop"}, nil })
+	_, err = app.Parse([]string{"+loop"})
+	assert.EqualError(t, err, `recursive inclusion of arguments file "loop"`)
+}
+
+func TestFileExpansionGlobalDefault(t *testing.T) {
+	defer func(previous bool) { EnableFileExpansion = previous }(EnableFileExpansion)
+	EnableFileExpansion = false
+
+	app := newTestApp()
+	arg := app.Arg("arg", "").String()
+	_, err := app.Parse([]string{"@file"})
+	assert.NoError(t, err)
+	assert.Equal(t, "@file", *arg)
+
+	app.FileExpansion(true).FileExpander(func(fs.FS, string) ([]string, error) {
+		return []string{"expanded"}, nil
+	})
+	_, err = app.Parse([]string{"@file"})
+	assert.NoError(t, err)
+	assert.Equal(t, "expanded", *arg)
+}
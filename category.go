@@ -0,0 +1,144 @@
+package kingpin
+
+// Categories declares the order in which the categories of flags and commands
+// are displayed in the usage. Categories that are not declared are displayed
+// after the declared ones, in the order of their first use.
+func (a *Application) Categories(names ...string) *Application {
+	a.categories = names
+	return a
+}
+
+// Category sets the category under which the flag is listed in the usage.
+func (f *FlagClause) Category(name string) *FlagClause {
+	f.category = name
+	return f
+}
+
+// Category sets the category under which the command (and its sub commands
+// without category) is listed in the usage.
+func (c *CmdClause) Category(name string) *CmdClause {
+	c.category = name
+	return c
+}
+
+// Returns the category of the command, inherited from its parents if not set.
+func (c *CmdClause) effectiveCategory() string {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.category != "" {
+			return cmd.category
+		}
+	}
+	return ""
+}
+
+// FlagCategoryModel represents the flags of a category. The flags without
+// category belong to a category without name.
+type FlagCategoryModel struct {
+	Name  string
+	Flags []*FlagModel
+}
+
+// CmdCategoryModel represents the commands of a category. The commands without
+// category belong to a category without name.
+type CmdCategoryModel struct {
+	Name      string
+	Commands  []*CmdModel // Direct commands of the category
+	flattened []*CmdModel
+}
+
+// FlattenedCommands returns the leaf commands of the category. Sub commands
+// without category belong to the category of their parent.
+func (c *CmdCategoryModel) FlattenedCommands() []*CmdModel {
+	return c.flattened
+}
+
+// Returns the categories in display order: no category first, then the
+// declared categories and finally the undeclared ones in order of use.
+func orderCategories(declared []string, used []string) []string {
+	seen := map[string]bool{}
+	present := map[string]bool{}
+	for _, name := range used {
+		present[name] = true
+	}
+	var out []string
+	add := func(name string) {
+		if present[name] && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	add("")
+	for _, name := range declared {
+		add(name)
+	}
+	for _, name := range used {
+		add(name)
+	}
+	return out
+}
+
+// Groups the flags by category. Categories without any displayed flag are
+// ignored: the hidden flags are never displayed, and the deprecated ones only
+// if withDeprecated is set.
+func flagCategories(declared []string, flags []*FlagModel, withDeprecated bool) []*FlagCategoryModel {
+	var used []string
+	byName := map[string]*FlagCategoryModel{}
+	for _, flag := range flags {
+		category := byName[flag.Category]
+		if category == nil {
+			category = &FlagCategoryModel{Name: flag.Category}
+			byName[flag.Category] = category
+		}
+		if !flag.Hidden && (withDeprecated || !flag.Deprecated) {
+			used = append(used, flag.Category)
+		}
+		category.Flags = append(category.Flags, flag)
+	}
+	var out []*FlagCategoryModel
+	for _, name := range orderCategories(declared, used) {
+		out = append(out, byName[name])
+	}
+	return out
+}
+
+// Groups the commands by category. Categories with only hidden commands are
+// ignored (except the one without name).
+func cmdCategories(declared []string, group *CmdGroupModel) []*CmdCategoryModel {
+	var used []string
+	byName := map[string]*CmdCategoryModel{}
+	category := func(name string, cmd *CmdModel) *CmdCategoryModel {
+		c := byName[name]
+		if c == nil {
+			c = &CmdCategoryModel{Name: name}
+			byName[name] = c
+		}
+		if name == "" || !cmd.Hidden {
+			used = append(used, name)
+		}
+		return c
+	}
+	for _, cmd := range group.Commands {
+		c := category(cmd.Category, cmd)
+		c.Commands = append(c.Commands, cmd)
+	}
+	var flatten func(cmds []*CmdModel, inherited string)
+	flatten = func(cmds []*CmdModel, inherited string) {
+		for _, cmd := range cmds {
+			name := cmd.Category
+			if name == "" {
+				name = inherited
+			}
+			c := category(name, cmd)
+			if len(cmd.Commands) == 0 {
+				c.flattened = append(c.flattened, cmd)
+			}
+			flatten(cmd.Commands, name)
+		}
+	}
+	flatten(group.Commands, "")
+	var out []*CmdCategoryModel
+	for _, name := range orderCategories(declared, used) {
+		out = append(out, byName[name])
+	}
+	return out
+}
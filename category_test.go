@@ -0,0 +1,115 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCategoryApp(buf *bytes.Buffer) *Application {
+	app := newTestApp().Writer(buf).Categories("Networking", "Output")
+	app.Flag("format", "Output format.").Category("Output").String()
+	app.Flag("port", "Port to use.").Category("Networking").Int()
+	app.Flag("verbose", "Verbose mode.").Bool()
+	app.Flag("internal", "Internal flag.").Category("Internal").Hidden().Bool()
+	app.Command("status", "Show the status.")
+	nodes := app.Command("nodes", "Manage the nodes.").Category("Cluster management")
+	nodes.Command("add", "Add a node.")
+	nodes.Command("remove", "Remove a node.").Category("Dangerous")
+	return app
+}
+
+func TestCategoriesModel(t *testing.T) {
+	app := newCategoryApp(&bytes.Buffer{})
+	assert.NoError(t, app.init())
+	model := app.Model()
+	assert.Equal(t, []string{"Networking", "Output"}, model.Categories)
+
+	var names []string
+	for _, category := range flagCategories(model.Categories, model.Flags, false) {
+		names = append(names, category.Name)
+	}
+	assert.Equal(t, []string{"", "Networking", "Output"}, names, "hidden categories are ignored")
+
+	categories := cmdCategories(model.Categories, model.CmdGroupModel)
+	assert.Len(t, categories, 3)
+	assert.Equal(t, "", categories[0].Name)
+	assert.Equal(t, "Cluster management", categories[1].Name)
+	assert.Equal(t, "Dangerous", categories[2].Name)
+	assert.Len(t, categories[1].Commands, 1)
+	assert.Len(t, categories[1].FlattenedCommands(), 1, "nodes add inherits the category of nodes")
+	assert.Equal(t, "nodes remove", categories[2].FlattenedCommands()[0].FullCommand)
+	assert.Equal(t, "Cluster management", app.GetCommand("nodes").GetCommand("add").Model().EffectiveCategory)
+}
+
+func TestCategoriesUsage(t *testing.T) {
+	var buf bytes.Buffer
+	app := newCategoryApp(&buf)
+
+	app.Usage(nil)
+	usage := buf.String()
+	assert.Contains(t, usage, "Flags:\n  --[no-]help ")
+	assert.Contains(t, usage, "Networking:\n  --port=PORT")
+	assert.Contains(t, usage, "Output:\n  --format=FORMAT")
+	assert.NotContains(t, usage, "Internal")
+	assert.Contains(t, usage, "Commands:\nhelp [<command>...]")
+	assert.Contains(t, usage, "Cluster management:\nnodes add")
+	assert.Contains(t, usage, "Dangerous:\nnodes remove")
+	assert.True(t, bytes.Index(buf.Bytes(), []byte("Networking:")) < bytes.Index(buf.Bytes(), []byte("Output:")))
+
+	templates := []string{CompactUsageTemplate, LongHelpTemplate, SeparateOptionalFlagsUsageTemplate}
+	for _, template := range templates {
+		buf.Reset()
+		app.UsageTemplate(template).Usage(nil)
+		assert.Contains(t, buf.String(), "Networking:\n")
+		assert.Contains(t, buf.String(), "Cluster management:\n")
+	}
+
+	buf.Reset()
+	app.UsageTemplate(ManPageTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), ".SH \"OPTIONS\"\n")
+	assert.Contains(t, buf.String(), ".SH \"Networking\"\n")
+	assert.Contains(t, buf.String(), ".SH \"COMMANDS\"\n")
+	assert.Contains(t, buf.String(), ".SH \"Cluster management\"\n")
+}
+
+func TestCategoriesIgnoreEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	app := newCategoryApp(&buf)
+	app.Flag("old", "Old flag.").Category("Legacy").Deprecated("").Bool()
+	assert.NoError(t, app.init())
+	model := app.Model()
+
+	var names []string
+	for _, category := range flagCategories(model.Categories, model.Flags, false) {
+		names = append(names, category.Name)
+	}
+	assert.Equal(t, []string{"", "Networking", "Output"}, names, "deprecated categories are ignored")
+	names = nil
+	for _, category := range flagCategories(model.Categories, model.Flags, true) {
+		names = append(names, category.Name)
+	}
+	assert.Equal(t, []string{"", "Networking", "Output", "Legacy"}, names)
+
+	app.Usage(nil)
+	assert.NotContains(t, buf.String(), "Legacy")
+
+	buf.Reset()
+	app.UsageTemplate(LongHelpTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), "Legacy:\n  --[no-]old  Old flag. (deprecated)\n", "the long help lists the deprecated flags")
+}
+
+func TestCategoriesRequiredFlags(t *testing.T) {
+	var buf bytes.Buffer
+	app := newCategoryApp(&buf).UsageTemplate(SeparateOptionalFlagsUsageTemplate)
+	app.Flag("host", "Host to use.").Category("Networking").Required().String()
+	app.Flag("token", "Token.").Required().String()
+
+	app.Usage(nil)
+	usage := buf.String()
+	assert.Contains(t, usage, "Required flags:\n  --token=TOKEN")
+	assert.Contains(t, usage, "Networking (required):\n  --host=HOST")
+	assert.Contains(t, usage, "Networking:\n  --port=PORT")
+	assert.NotContains(t, usage, "Networking:\n  --host")
+}
@@ -0,0 +1,59 @@
+package kingpin
+
+import "io"
+
+// CloseAfterActions instructs Parse to close the values holding resources
+// (see Close) once the actions have been executed.
+func (a *Application) CloseAfterActions() *Application {
+	a.closeAfterActions = true
+	return a
+}
+
+// Close closes the flag and argument values implementing io.Closer that have
+// been set by the parses since the last Close, such as the files opened by
+// File() and OpenFile() (stdin and stdout are never closed, and the target of
+// these values is set to nil). The values set by a failed Parse are
+// automatically closed. The first error encountered is returned.
+func (a *Application) Close() error {
+	a.openedMutex.Lock()
+	opened := a.opened
+	a.opened = nil
+	a.openedMutex.Unlock()
+	return closeAll(opened)
+}
+
+// Hands over values holding resources to the application, see Close.
+func (a *Application) addOpened(closers ...io.Closer) {
+	a.openedMutex.Lock()
+	defer a.openedMutex.Unlock()
+	for _, closer := range closers {
+		a.opened = appendCloser(a.opened, closer)
+	}
+}
+
+// Registers the value set by the parse if it holds resources, so that it is
+// closed if the parse fails or handed over to the application otherwise.
+func (p *ParseContext) trackCloser(value Value) {
+	if closer, ok := value.(io.Closer); ok {
+		p.opened = appendCloser(p.opened, closer)
+	}
+}
+
+func appendCloser(closers []io.Closer, closer io.Closer) []io.Closer {
+	for _, c := range closers {
+		if c == closer {
+			return closers
+		}
+	}
+	return append(closers, closer)
+}
+
+// Closes all the closers and returns the first error encountered.
+func closeAll(closers []io.Closer) (err error) {
+	for _, closer := range closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
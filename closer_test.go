@@ -0,0 +1,141 @@
+package kingpin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(filename, []byte("content"), 0600))
+
+	app := newTestApp()
+	input := app.Flag("input", "").File()
+	output := app.Flag("output", "").OpenFile(os.O_WRONLY, 0)
+	_, err := app.Parse([]string{"--input", filename, "--output", "-"})
+	assert.NoError(t, err)
+	assert.Equal(t, os.Stdout, *output)
+	_, err = (*input).Stat()
+	assert.NoError(t, err)
+
+	opened := *input
+	assert.NoError(t, app.Close())
+	_, err = opened.Stat()
+	assert.Error(t, err, "the file must be closed")
+	assert.Nil(t, *input)
+	_, err = os.Stdout.Stat()
+	assert.NoError(t, err)
+	assert.NoError(t, app.Close())
+
+	_, err = app.Parse([]string{"--input", "-"})
+	assert.NoError(t, err)
+	assert.Equal(t, os.Stdin, *input)
+}
+
+func TestCloseFilesOnError(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(filename, []byte("content"), 0600))
+
+	app := newTestApp()
+	input := app.Flag("input", "").File()
+	app.Flag("count", "").Int()
+	_, err := app.Parse([]string{"--input", filename, "--count=x"})
+	assert.Error(t, err)
+	assert.Nil(t, *input, "the file must be closed")
+}
+
+func TestCloseAfterActions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(filename, []byte("content"), 0600))
+
+	app := newTestApp().CloseAfterActions()
+	input := app.Arg("input", "").File()
+	app.Action(func(*ParseContext) error {
+		_, err := (*input).Stat()
+		return err
+	})
+	_, err := app.Parse([]string{filename})
+	assert.NoError(t, err)
+	assert.Nil(t, *input, "the file must be closed")
+}
+
+func TestFileValueClosesPreviousFile(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	for _, name := range []string{first, second} {
+		assert.NoError(t, os.WriteFile(name, nil, 0600))
+	}
+	var target *os.File
+	value := newFileValue(&target, os.O_RDONLY, 0)
+	assert.NoError(t, value.Set(first))
+	previous := target
+	assert.NoError(t, value.Set(second))
+	_, err := previous.Stat()
+	assert.Error(t, err)
+	assert.Equal(t, second, target.Name())
+	value.Reset()
+	assert.Nil(t, target)
+}
+
+type closerValue struct {
+	stringValue
+	closed bool
+}
+
+func (c *closerValue) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCloseOnlyOpenedFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(filename, []byte("content"), 0600))
+
+	app := newTestApp()
+	custom := &closerValue{stringValue: *newStringValue(new(string))}
+	app.Flag("custom", "").SetValue(custom)
+	input := app.Flag("input", "").File()
+	_, err := app.Parse(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, app.opened)
+
+	for i := 0; i < 2; i++ {
+		_, err = app.Parse([]string{"--input", filename})
+		assert.NoError(t, err)
+	}
+	assert.Len(t, app.opened, 1)
+	assert.NoError(t, app.Close())
+	assert.Nil(t, *input)
+	assert.Empty(t, app.opened)
+	assert.False(t, custom.closed, "values which have not been set are not closed")
+
+	_, err = app.Parse([]string{"--custom=value"})
+	assert.NoError(t, err)
+	assert.NoError(t, app.Close())
+	assert.True(t, custom.closed, "the values implementing io.Closer are closed")
+}
+
+func TestCloseOnErrorOnlyFilesOfTheParse(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	for _, name := range []string{first, second} {
+		assert.NoError(t, os.WriteFile(name, nil, 0600))
+	}
+
+	app := newTestApp()
+	input := app.Flag("input", "").File()
+	output := app.Flag("output", "").OpenFile(os.O_WRONLY, 0)
+	app.Flag("count", "").Int()
+	_, err := app.Parse([]string{"--input", first})
+	assert.NoError(t, err)
+	_, err = app.Parse([]string{"--output", second, "--count=x"})
+	assert.Error(t, err)
+	assert.Nil(t, *output, "the file of the failed parse must be closed")
+	_, err = (*input).Stat()
+	assert.NoError(t, err, "the file of the previous parse must stay open")
+	assert.NoError(t, app.Close())
+	assert.Nil(t, *input)
+}
@@ -10,6 +10,7 @@ type cmdMixin struct {
 	*argGroup
 	*cmdGroup
 	actionMixin
+	examples []example
 }
 
 // CmdCompletion returns completion options for arguments, if that's where
@@ -64,7 +65,7 @@ ElementLoop:
 				}
 			}
 		case *CmdClause:
-			options = append(options, clause.completionAlts...)
+			options = append(options, context.completionAlts[clause]...)
 		default:
 		}
 	}
@@ -79,6 +80,10 @@ ElementLoop:
 				options = append(options, cmd.name)
 			}
 		}
+		if app := c.cmdGroup.app; app != nil && c.cmdGroup == app.cmdGroup {
+			// User aliases are only available at the top level
+			options = append(options, app.userAliasNames()...)
+		}
 	}
 
 	return options
@@ -214,15 +219,16 @@ type CmdClauseValidator func(*CmdClause) error
 // and either subcommands or positional arguments.
 type CmdClause struct {
 	cmdMixin
-	app            *Application
-	name           string
-	aliases        []string
-	help           string
-	helpLong       string
-	isDefault      bool
-	validator      CmdClauseValidator
-	hidden         bool
-	completionAlts []string
+	deprecationMixin
+	app       *Application
+	name      string
+	aliases   []string
+	help      string
+	helpLong  string
+	isDefault bool
+	validator CmdClauseValidator
+	hidden    bool
+	category  string
 }
 
 func newCommand(app *Application, name, help string) *CmdClause {
@@ -302,6 +308,35 @@ func (c *CmdClause) PreAction(action Action) *CmdClause {
 	return c
 }
 
+// ActionCtx is like Action, but the callback also receives the context supplied
+// to Application.ParseWithContext.
+func (c *CmdClause) ActionCtx(action ActionCtx) *CmdClause {
+	return c.Action(WithContext(action))
+}
+
+// PreActionCtx is like PreAction, but the callback also receives the context
+// supplied to Application.ParseWithContext.
+func (c *CmdClause) PreActionCtx(action ActionCtx) *CmdClause {
+	return c.PreAction(WithContext(action))
+}
+
+// PostAction called after the actions, even if they failed, once the
+// pre-actions of the clause ran. Post actions are executed in the reverse
+// order of the command line, and in the reverse order of their registration
+// for a given clause.
+func (c *CmdClause) PostAction(action Action) *CmdClause {
+	c.addPostAction(action)
+	return c
+}
+
+// OnError called with any error returned once the command is selected, by the
+// command itself or by its flags, arguments and subcommands, before the post
+// actions. The error actions are executed in reverse command-tree order.
+func (c *CmdClause) OnError(action ErrorAction) *CmdClause {
+	c.addErrorAction(action)
+	return c
+}
+
 // Help sets the help message.
 func (c *CmdClause) Help(help string) *CmdClause {
 	c.help = help
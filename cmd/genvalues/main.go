@@ -34,6 +34,12 @@ func (f *{{.|ValueName}}) Get() interface{} { return ({{.Type}})(*f.v) }
 
 func (f *{{.|ValueName}}) String() string { return {{.|Format}} }
 
+func (f *{{.|ValueName}}) clone() Value { return new{{.|Name}}Value(new({{.Type}})) }
+
+func (f *{{.|ValueName}}) Reset() { *f.v = *new({{.Type}}) }
+
+func (f *{{.|ValueName}}) target() interface{} { return f.v }
+
 {{if .Help}}
 // {{.Help}}
 {{else -}}
@@ -19,7 +19,7 @@ func parseAndExecute(app *Application, context *ParseContext) (string, error) {
 		return "", err
 	}
 
-	return app.execute(context, selected)
+	return app.execute(context, selected, nil, nil)
 }
 
 func complete(t *testing.T, app *Application, args ...string) []string {
@@ -46,7 +46,7 @@ func TestNestedCommands(t *testing.T) {
 	sub2.Flag("sub2", "")
 	sub2.Command("sub2sub1", "")
 
-	context := tokenize([]string{"sub1", "sub1sub1", "sub1sub1end"}, false)
+	context := tokenize([]string{"sub1", "sub1sub1", "sub1sub1end"}, false, nil)
 	selected, err := parseAndExecute(app, context)
 	assert.NoError(t, err)
 	assert.True(t, context.EOL())
@@ -58,7 +58,7 @@ func TestNestedCommandsWithArgs(t *testing.T) {
 	cmd := app.Command("a", "").Command("b", "")
 	a := cmd.Arg("a", "").String()
 	b := cmd.Arg("b", "").String()
-	context := tokenize([]string{"a", "b", "c", "d"}, false)
+	context := tokenize([]string{"a", "b", "c", "d"}, false, nil)
 	selected, err := parseAndExecute(app, context)
 	assert.NoError(t, err)
 	assert.True(t, context.EOL())
@@ -74,7 +74,7 @@ func TestNestedCommandsWithFlags(t *testing.T) {
 	b := cmd.Flag("bbb", "").Short('b').String()
 	err := app.init()
 	assert.NoError(t, err)
-	context := tokenize(strings.Split("a b --aaa x -b x", " "), false)
+	context := tokenize(strings.Split("a b --aaa x -b x", " "), false, nil)
 	selected, err := parseAndExecute(app, context)
 	assert.NoError(t, err)
 	assert.True(t, context.EOL())
@@ -93,7 +93,7 @@ func TestNestedCommandWithMergedFlags(t *testing.T) {
 	cmd00f0 := cmd00.Flag("aaflag", "").Bool()
 	err := app.init()
 	assert.NoError(t, err)
-	context := tokenize(strings.Split("a aa --aflag --aaflag", " "), false)
+	context := tokenize(strings.Split("a aa --aflag --aaflag", " "), false, nil)
 	selected, err := parseAndExecute(app, context)
 	assert.NoError(t, err)
 	assert.True(t, *cmd0f0)
@@ -120,7 +120,7 @@ func TestNestedCommandWithArgAndMergedFlags(t *testing.T) {
 	cmd00f0 := cmd00.Flag("aaflag", "").Bool()
 	err := app.init()
 	assert.NoError(t, err)
-	context := tokenize(strings.Split("a aa hello --aflag --aaflag", " "), false)
+	context := tokenize(strings.Split("a aa hello --aflag --aaflag", " "), false, nil)
 	selected, err := parseAndExecute(app, context)
 	assert.NoError(t, err)
 	assert.True(t, *cmd0f0)
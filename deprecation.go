@@ -0,0 +1,148 @@
+package kingpin
+
+import (
+	"fmt"
+	"strings"
+)
+
+type deprecationMixin struct {
+	deprecated         bool
+	deprecationMessage string
+}
+
+func (d *deprecationMixin) setDeprecated(message string) {
+	d.deprecated = true
+	d.deprecationMessage = message
+}
+
+// Returns the warning printed when a deprecated clause is used, translated
+// with the catalog.
+func (d *deprecationMixin) deprecationWarning(catalog MessageCatalog, kind, name string) string {
+	if d.deprecationMessage == "" {
+		return fmt.Sprintf(translate(catalog, "%s %s is deprecated"), translate(catalog, kind), name)
+	}
+	return fmt.Sprintf(translate(catalog, "%s %s is deprecated, %s"), translate(catalog, kind), name, d.deprecationMessage)
+}
+
+// Deprecated marks the flag as deprecated. The flag keeps working, but a
+// warning including the message is printed the first time it is used and the
+// flag is only listed in the long help.
+func (f *FlagClause) Deprecated(message string) *FlagClause {
+	f.setDeprecated(message)
+	return f
+}
+
+// ReplacedBy marks the flag as deprecated (see Deprecated) and forwards its
+// value to the named flag, which must be defined on the same command or on
+// the application.
+func (f *FlagClause) ReplacedBy(name string) *FlagClause {
+	message := f.deprecationMessage
+	if message == "" {
+		message = fmt.Sprintf("use --%s instead", name)
+	}
+	f.setDeprecated(message)
+	f.replacedBy = name
+	return f
+}
+
+// Deprecated marks the command as deprecated. The command keeps working, but
+// a warning including the message is printed the first time it is used and
+// the command is only listed in the long help.
+func (c *CmdClause) Deprecated(message string) *CmdClause {
+	c.setDeprecated(message)
+	return c
+}
+
+// Deprecated marks the argument as deprecated. The argument keeps working, but
+// a warning including the message is printed the first time it is used.
+func (a *ArgClause) Deprecated(message string) *ArgClause {
+	a.setDeprecated(message)
+	return a
+}
+
+// Returns the flag to which the value is forwarded, if any.
+func (f *FlagClause) replacement(context *ParseContext) *FlagClause {
+	if f.replacedBy == "" {
+		return nil
+	}
+	return context.flags.long[f.replacedBy]
+}
+
+// Checks that the flags are replaced by existing flags of their group or of
+// the parent groups.
+func checkReplacements(visible []*flagGroup, flags *flagGroup, cmds *cmdGroup) error {
+	visible = append(visible[:len(visible):len(visible)], flags)
+	for _, flag := range flags.flagOrder {
+		if flag.replacedBy == "" {
+			continue
+		}
+		found := false
+		for _, group := range visible {
+			if _, found = group.long[flag.replacedBy]; found {
+				break
+			}
+		}
+		if !found || flag.replacedBy == flag.name {
+			return fmt.Errorf("flag '--%s' is replaced by undefined flag '--%s'", flag.name, flag.replacedBy)
+		}
+	}
+	for _, cmd := range cmds.commandOrder {
+		if err := checkReplacements(visible, cmd.flagGroup, cmd.cmdGroup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prints a warning for the deprecated clauses used in the parse context. Each
+// clause is only reported once per parse.
+func (a *Application) warnDeprecated(context *ParseContext) {
+	warned := map[interface{}]bool{}
+	for _, element := range context.Elements {
+		var warning string
+		switch clause := element.Clause.(type) {
+		case *FlagClause:
+			if clause.deprecated {
+				warning = clause.deprecationWarning(a.messages, "flag", "'--"+clause.name+"'")
+			}
+		case *CmdClause:
+			if clause.deprecated {
+				warning = clause.deprecationWarning(a.messages, "command", "'"+clause.FullCommand()+"'")
+			}
+		case *ArgClause:
+			if clause.deprecated {
+				warning = clause.deprecationWarning(a.messages, "argument", "'"+clause.name+"'")
+			}
+		}
+		if warning == "" || warned[element.Clause] {
+			continue
+		}
+		warned[element.Clause] = true
+		fmt.Fprintf(a.errorWriter, "%s: %s: %s\n", a.Name, a.translate("warning"), warning)
+	}
+}
+
+// Returns the notice appended to the help of deprecated clauses, translated
+// with the catalog.
+func deprecationNotice(catalog MessageCatalog, deprecated bool, message string) string {
+	if !deprecated {
+		return ""
+	}
+	if message == "" {
+		return translate(catalog, "(deprecated)")
+	}
+	return fmt.Sprintf(translate(catalog, "(deprecated: %s)"), strings.TrimSuffix(message, "."))
+}
+
+// Returns the deprecation notice of a flag, argument or command model.
+func modelDeprecationNotice(model interface{}, catalog MessageCatalog) string {
+	switch m := model.(type) {
+	case *FlagModel:
+		return deprecationNotice(catalog, m.Deprecated, m.DeprecationNote)
+	case *ArgModel:
+		return deprecationNotice(catalog, m.Deprecated, m.DeprecationNote)
+	case *CmdModel:
+		return deprecationNotice(catalog, m.Deprecated, m.DeprecationNote)
+	}
+	return ""
+}
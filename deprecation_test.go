@@ -0,0 +1,92 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecatedWarning(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().ErrorWriter(&buf)
+	old := app.Flag("old", "").Deprecated("it will be removed in v3").String()
+	cmd := app.Command("legacy", "").Deprecated("")
+	cmd.Arg("arg", "").Deprecated("use --name instead").String()
+	app.Command("other", "")
+
+	_, err := app.Parse([]string{"--old=value", "legacy", "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "value", *old)
+	assert.Equal(t, ""+
+		"test: warning: flag '--old' is deprecated, it will be removed in v3\n"+
+		"test: warning: command 'legacy' is deprecated\n"+
+		"test: warning: argument 'arg' is deprecated, use --name instead\n", buf.String())
+
+	buf.Reset()
+	_, err = app.Parse([]string{"--old=a", "--old=b", "other"})
+	assert.Error(t, err)
+	assert.Equal(t, "test: warning: flag '--old' is deprecated, it will be removed in v3\n", buf.String(), "warnings are only printed once per parse")
+
+	buf.Reset()
+	_, err = newTestApp().ErrorWriter(&buf).Parse(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestReplacedBy(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().ErrorWriter(&buf)
+	region := app.Flag("region", "").Required().String()
+	zones := app.Flag("zone", "").Strings()
+	cmd := app.Command("cmd", "")
+	cmd.Flag("old-region", "").ReplacedBy("region").String()
+	cmd.Flag("old-zone", "").ReplacedBy("zone").Strings()
+
+	_, err := app.Parse([]string{"cmd", "--old-region=east", "--old-zone=a", "--zone=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "east", *region)
+	assert.Equal(t, []string{"a", "b"}, *zones)
+	assert.Contains(t, buf.String(), "flag '--old-region' is deprecated, use --region instead")
+
+	result, err := app.ParseResult([]string{"cmd", "--old-region=west"})
+	assert.NoError(t, err)
+	assert.Equal(t, "west", result.String("region"))
+
+	model := app.GetCommand("cmd").GetFlag("old-region").Model()
+	assert.True(t, model.Deprecated)
+	assert.Equal(t, "region", model.ReplacedBy)
+	assert.Equal(t, "(deprecated: use --region instead)", model.DeprecationNotice())
+
+	app = newTestApp()
+	app.Flag("old", "").ReplacedBy("undefined").String()
+	_, err = app.Parse(nil)
+	assert.EqualError(t, err, "flag '--old' is replaced by undefined flag '--undefined'")
+}
+
+func TestDeprecatedUsage(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf)
+	app.Flag("old", "Old flag.").Deprecated("use --new").String()
+	app.Flag("new", "New flag.").String()
+	app.Command("legacy", "Legacy command.").Deprecated("")
+	app.Command("current", "Current command.")
+
+	for _, template := range []string{DefaultUsageTemplate, CompactUsageTemplate, SeparateOptionalFlagsUsageTemplate} {
+		buf.Reset()
+		app.UsageTemplate(template).Usage(nil)
+		assert.Contains(t, buf.String(), "New flag.")
+		assert.Contains(t, buf.String(), "current")
+		assert.NotContains(t, buf.String(), "--old")
+		assert.NotContains(t, buf.String(), "legacy")
+	}
+
+	for _, template := range []string{LongHelpTemplate, ManPageTemplate} {
+		buf.Reset()
+		app.UsageTemplate(template).Usage(nil)
+		assert.Contains(t, buf.String(), "--old")
+		assert.Contains(t, buf.String(), "(deprecated: use --new)")
+		assert.Contains(t, buf.String(), "legacy")
+		assert.Contains(t, buf.String(), "(deprecated)")
+	}
+}
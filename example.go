@@ -0,0 +1,168 @@
+package kingpin
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type example struct {
+	cmdline string
+	help    string
+}
+
+// Example adds an example of invocation of the application to the usage. The
+// command line is written without the application name and is checked
+// against the flags and commands of the application during initialization:
+// the values and the required flags and arguments are checked too, except the
+// values referencing a variable ($VAR) or naming a file.
+func (a *Application) Example(cmdline, help string) *Application {
+	a.examples = append(a.examples, example{cmdline, help})
+	return a
+}
+
+// Example adds an example of invocation of the command to the usage. The
+// command line is written without the application name and must select the
+// command (or one of its sub commands).
+func (c *CmdClause) Example(cmdline, help string) *CmdClause {
+	c.examples = append(c.examples, example{cmdline, help})
+	return c
+}
+
+// ExampleModel represents an example of invocation.
+type ExampleModel struct {
+	Command string // Command line, including the application name
+	Help    string
+}
+
+func examplesModel(name string, examples []example) []*ExampleModel {
+	var out []*ExampleModel
+	for _, e := range examples {
+		out = append(out, &ExampleModel{Command: name + " " + e.cmdline, Help: e.help})
+	}
+	return out
+}
+
+// AllExamples returns the examples of the application followed by those of
+// its visible commands.
+func (a *ApplicationModel) AllExamples() []*ExampleModel {
+	out := a.Examples
+	var walk func(cmds []*CmdModel)
+	walk = func(cmds []*CmdModel) {
+		for _, cmd := range cmds {
+			if !cmd.Hidden {
+				out = append(out, cmd.Examples...)
+				walk(cmd.Commands)
+			}
+		}
+	}
+	walk(a.Commands)
+	return out
+}
+
+// Checks that the examples of the application and of its commands are valid
+// command lines.
+func (a *Application) checkExamples() error {
+	for _, e := range a.examples {
+		if _, err := a.checkExample(e.cmdline); err != nil {
+			return fmt.Errorf("invalid example %q: %s", e.cmdline, err)
+		}
+	}
+	var check func(cmds *cmdGroup) error
+	check = func(cmds *cmdGroup) error {
+		for _, cmd := range cmds.commandOrder {
+			for _, e := range cmd.examples {
+				context, err := a.checkExample(e.cmdline)
+				if err != nil {
+					return fmt.Errorf("invalid example %q for command '%s': %s", e.cmdline, cmd.FullCommand(), err)
+				}
+				selected := context.SelectedCommand
+				for selected != nil && selected != cmd {
+					selected = selected.parent
+				}
+				if selected == nil {
+					return fmt.Errorf("example %q does not invoke command '%s'", e.cmdline, cmd.FullCommand())
+				}
+			}
+			if err := check(cmd.cmdGroup); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return check(a.cmdGroup)
+}
+
+// Replaces the variables of the examples, whose values are not checked.
+const exampleVariable = "\x00"
+
+// Parses an example and checks its values without setting them.
+func (a *Application) checkExample(cmdline string) (*ParseContext, error) {
+	words, err := splitShellWords(cmdline, a.fileExpansion.resolve().prefix, func(string) string { return exampleVariable })
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, len(words))
+	for _, word := range words {
+		args = append(args, word.value)
+	}
+	if args, err = a.expandUserAliases(args); err != nil {
+		return nil, err
+	}
+	context := tokenize(args, false, &fileExpansion{enabled: new(bool)})
+	context.flags.autoShortcut = a.autoShortcut
+	context.allowUnmanaged = a.allowUnmanaged
+	context.readOnly = true
+	if err := parse(context, a); err != nil {
+		return nil, err
+	}
+	return context, a.checkExampleValues(context)
+}
+
+// Checks the values of an example on clones of the values, then its required
+// flags and arguments, unless it uses a built-in flag such as --help.
+func (a *Application) checkExampleValues(context *ParseContext) error {
+	clones := map[Value]Value{}
+	for _, element := range context.Elements {
+		var value Value
+		switch clause := element.Clause.(type) {
+		case *FlagClause:
+			if _, ok := builtinFlagHelp[clause.name]; ok && a.flagGroup.long[clause.name] == clause {
+				return nil
+			}
+			if clause.allowFileValue && (*element.Value == "-" || strings.HasPrefix(*element.Value, "@")) {
+				continue
+			}
+			value = clause.value
+		case *ArgClause:
+			value = clause.value
+		case *CmdClause:
+			if clause.cmdGroup.have() && context.SelectedCommand == clause {
+				return context.errorf("must select a subcommand of '%s'", clause.FullCommand())
+			}
+			continue
+		}
+		if strings.Contains(*element.Value, exampleVariable) || accessesFiles(value) {
+			continue
+		}
+		if _, ok := clones[value]; !ok {
+			clones[value] = cloneValue(value)
+		}
+		if err := clones[value].Set(*element.Value); err != nil {
+			return err
+		}
+	}
+	return checkRequired(context)
+}
+
+// Returns true if setting the value accesses the file system.
+func accessesFiles(value Value) bool {
+	if a, ok := value.(*accumulator); ok {
+		value = a.element(reflect.New(a.typ).Interface())
+	}
+	switch value.(type) {
+	case *fileValue, *fileStatValue, *secretFileValue:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExamples(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).Example("--verbose status", "Show the status verbosely.")
+	verbose := app.Flag("verbose", "").Bool()
+	app.Command("status", "")
+	nodes := app.Command("nodes", "").Example("nodes add 'node 1'", "Add a node.")
+	nodes.Command("add", "").Arg("name", "").Required().String()
+	app.Command("hidden", "").Hidden().Example("hidden", "Hidden example.")
+
+	assert.NoError(t, app.init())
+	assert.False(t, *verbose, "examples are checked without setting values")
+	model := app.Model()
+	assert.Equal(t, []*ExampleModel{{Command: "test --verbose status", Help: "Show the status verbosely."}}, model.Examples)
+	assert.Len(t, model.AllExamples(), 2)
+	assert.Equal(t, "test nodes add 'node 1'", model.AllExamples()[1].Command)
+
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "Examples:\n  test --verbose status\n    Show the status verbosely.\n")
+	assert.NotContains(t, buf.String(), "nodes add 'node 1'")
+
+	buf.Reset()
+	app.Usage([]string{"nodes"})
+	assert.Contains(t, buf.String(), "Examples:\n  test nodes add 'node 1'\n    Add a node.\n")
+	assert.NotContains(t, buf.String(), "--verbose status")
+
+	buf.Reset()
+	app.UsageTemplate(LongHelpTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), "test --verbose status")
+	assert.Contains(t, buf.String(), "test nodes add 'node 1'")
+	assert.NotContains(t, buf.String(), "Hidden example.")
+
+	buf.Reset()
+	app.UsageTemplate(ManPageTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), ".SH \"EXAMPLES\"\n.TP\n\\fBtest --verbose status\\fR\nShow the status verbosely.\n")
+}
+
+func TestInvalidExamples(t *testing.T) {
+	app := newTestApp().Example("--unknown", "")
+	_, err := app.Parse(nil)
+	assert.EqualError(t, err, `invalid example "--unknown": unknown long flag '--unknown'`)
+
+	app = newTestApp()
+	app.Command("status", "")
+	app.Command("nodes", "").Example("nodes remove", "")
+	_, err = app.Parse([]string{"status"})
+	assert.EqualError(t, err, `invalid example "nodes remove" for command 'nodes': unexpected remove`)
+
+	app = newTestApp()
+	app.Command("status", "")
+	app.Command("nodes", "").Example("status", "")
+	_, err = app.Parse([]string{"status"})
+	assert.EqualError(t, err, `example "status" does not invoke command 'nodes'`)
+}
+
+func TestExampleValuesChecked(t *testing.T) {
+	newApp := func() *Application {
+		app := newTestApp()
+		app.Flag("count", "").Int()
+		app.Flag("input", "").File()
+		nodes := app.Command("nodes", "")
+		add := nodes.Command("add", "")
+		add.Flag("zone", "").Required().Enum("eu", "us")
+		add.Arg("name", "").Required().String()
+		return app
+	}
+
+	app := newApp()
+	app.GetCommand("nodes").Example("nodes add --zone=eu", "")
+	_, err := app.Parse([]string{"nodes", "add", "--zone=eu", "n1"})
+	assert.EqualError(t, err, `invalid example "nodes add --zone=eu" for command 'nodes': required argument 'name' not provided`)
+
+	app = newApp()
+	app.GetCommand("nodes").Example("nodes add n1", "")
+	_, err = app.Parse([]string{"nodes", "add", "--zone=eu", "n1"})
+	assert.EqualError(t, err, `invalid example "nodes add n1" for command 'nodes': required flag(s) '--zone' not provided`)
+
+	app = newApp()
+	app.GetCommand("nodes").Example("nodes add --zone=asia n1", "")
+	_, err = app.Parse([]string{"nodes", "add", "--zone=eu", "n1"})
+	assert.EqualError(t, err, `invalid example "nodes add --zone=asia n1" for command 'nodes': enum value must be one of eu,us, got 'asia'`)
+
+	app = newApp()
+	app.GetCommand("nodes").Example("nodes", "")
+	_, err = app.Parse([]string{"nodes", "add", "--zone=eu", "n1"})
+	assert.EqualError(t, err, `invalid example "nodes" for command 'nodes': must select a subcommand of 'nodes'`)
+
+	app = newApp().Example("--count=$COUNT --input=/missing/file nodes add --zone=$ZONE n1", "").Example("--help", "")
+	_, err = app.Parse([]string{"nodes", "add", "--zone=eu", "n1"})
+	assert.NoError(t, err, "the variables and the files are not checked")
+}
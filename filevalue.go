@@ -0,0 +1,107 @@
+package kingpin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DefaultFileValueSizeLimit is the maximum number of bytes loaded in a flag
+// value from a file or stdin (see FlagClause.AllowFileValue()).
+var DefaultFileValueSizeLimit int64 = 1 << 20
+
+// Stdin sets the reader from which the flag values given as - are loaded (see
+// FlagClause.AllowFileValue() and FlagClause.Secret()). The default is
+// os.Stdin.
+func (a *Application) Stdin(r io.Reader) *Application {
+	a.stdin = r
+	return a
+}
+
+// AllowFileValue allows the flag value to be loaded from a file by using
+// --flag @filename, or from stdin by using --flag -. A value starting with
+// @@ is used as is, without its first @.
+//
+// The size of the content is limited to DefaultFileValueSizeLimit unless
+// FileValueSizeLimit() is used.
+func (f *FlagClause) AllowFileValue() *FlagClause {
+	f.allowFileValue = true
+	return f
+}
+
+// FileValueSizeLimit sets the maximum size of the content that can be loaded
+// in the flag value from a file. It implies AllowFileValue().
+func (f *FlagClause) FileValueSizeLimit(bytes int64) *FlagClause {
+	f.allowFileValue = true
+	f.fileValueLimit = bytes
+	return f
+}
+
+// Implemented by the values accepting - to designate stdin or stdout.
+type stdioValue interface {
+	acceptsStdio()
+}
+
+// Returns true if the flag accepts - as a value designating stdin or stdout.
+func (f *FlagClause) acceptsDash() bool {
+	_, ok := f.value.(stdioValue)
+	return ok || f.allowFileValue
+}
+
+// Returns the actual value of the flag, loading it from a file (or from stdin)
+// if required.
+func (f *FlagClause) resolveFileValue(value string, stdin io.Reader) (string, error) {
+	if !f.allowFileValue {
+		return value, nil
+	}
+	var path string
+	switch {
+	case value == "-":
+		path = value
+	case strings.HasPrefix(value, "@@"):
+		return value[1:], nil
+	case strings.HasPrefix(value, "@"):
+		if path = value[1:]; path == "" {
+			return "", fmt.Errorf("expected file name after @ for flag '--%s'", f.name)
+		}
+	default:
+		return value, nil
+	}
+
+	limit := f.fileValueLimit
+	if limit <= 0 {
+		limit = DefaultFileValueSizeLimit
+	}
+	content, err := readValueFile(path, limit, stdin)
+	if err != nil {
+		return "", fmt.Errorf("unable to read value of '--%s': %s", f.name, err)
+	}
+	return string(content), nil
+}
+
+// Reads the content of a file (or stdin if path is -) up to limit bytes. stdin
+// is nil if it is not available.
+func readValueFile(path string, limit int64, stdin io.Reader) ([]byte, error) {
+	r := stdin
+	source := "stdin"
+	if path == "-" && stdin == nil {
+		return nil, fmt.Errorf("stdin is not available")
+	} else if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+		source = path
+	}
+	content, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", source, err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("content of %s exceeds %d bytes", source, limit)
+	}
+	return content, nil
+}
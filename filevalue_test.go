@@ -0,0 +1,59 @@
+package kingpin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagFileValue(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "payload.json")
+	assert.NoError(t, os.WriteFile(filename, []byte(`{"key": "value"}`), 0600))
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{"Plain value", []string{"--body", "text"}, "text", ""},
+		{"File", []string{"--body", "@" + filename}, `{"key": "value"}`, ""},
+		{"File with equal", []string{"--body=@" + filename}, `{"key": "value"}`, ""},
+		{"Escaped", []string{"--body", "@@mention"}, "@mention", ""},
+		{"Stdin", []string{"--body", "-"}, "from stdin", ""},
+		{"Missing file", []string{"--body", "@" + filename + ".missing"}, "", "unable to read value of '--body': open "},
+		{"No file name", []string{"--body", "@"}, "", "expected file name after @ for flag '--body'"},
+		{"Too large", []string{"--small", "@" + filename}, "", "unable to read value of '--small': content of " + filename + " exceeds 4 bytes"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			app := newTestApp().Stdin(strings.NewReader("from stdin"))
+			body := app.Flag("body", "").AllowFileValue().String()
+			app.Flag("small", "").FileValueSizeLimit(4).String()
+			_, err := app.Parse(c.args)
+			if c.err != "" {
+				if assert.Error(t, err) {
+					assert.True(t, strings.HasPrefix(err.Error(), c.err), err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, *body)
+		})
+	}
+}
+
+func TestFlagFileValueNotAllowed(t *testing.T) {
+	app := newTestApp()
+	body := app.Flag("body", "").String()
+	_, err := app.Parse([]string{"--body=@payload.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "@payload.json", *body)
+
+	_, err = app.Stdin(strings.NewReader("from stdin")).Parse([]string{"--body=-"})
+	assert.NoError(t, err)
+	assert.Equal(t, "-", *body)
+}
@@ -2,6 +2,7 @@ package kingpin
 
 import (
 	"fmt"
+	"io"
 )
 
 type flagGroup struct {
@@ -67,6 +68,7 @@ func (f *flagGroup) DeleteFlag(name string) error {
 }
 
 func (f *flagGroup) init(defaultEnvarPrefix string) error {
+	f.addSecretFileFlags()
 	if err := f.checkDuplicates(); err != nil {
 		return err
 	}
@@ -121,14 +123,14 @@ func (f *flagGroup) parse(context *ParseContext) (*FlagClause, error) {
 			if flag, invert, err = f.getFlagAlias(name); err != nil {
 				return nil, err
 			} else if flag == nil {
-				err = fmt.Errorf("unknown long flag '%s'", flagToken)
+				err = context.errorf("unknown long flag '%s'", flagToken)
 			}
 		} else if flag, ok = f.short[name]; !ok {
-			err = fmt.Errorf("unknown short flag '%s'", flagToken)
+			err = context.errorf("unknown short flag '%s'", flagToken)
 		}
 
 		if err != nil {
-			if context.appUnmanagedArgs == nil {
+			if !context.allowUnmanaged {
 				return nil, err
 			}
 
@@ -156,12 +158,14 @@ func (f *flagGroup) parse(context *ParseContext) (*FlagClause, error) {
 				}
 			}
 
-			context.appUnmanagedArgs.Unmanaged = append(context.appUnmanagedArgs.Unmanaged, current)
+			context.unmanaged = append(context.unmanaged, current)
 			return nil, nil
 		}
 
 		context.Next()
-		flag.isSetByUser()
+		if !context.readOnly {
+			flag.isSetByUser()
+		}
 
 		if fb, ok := flag.value.(boolFlag); ok && fb.IsBoolFlag() {
 			if invert {
@@ -169,11 +173,22 @@ func (f *flagGroup) parse(context *ParseContext) (*FlagClause, error) {
 			} else {
 				defaultValue = "true"
 			}
+			if flag.acceptsTopic && len(context.peek) > 0 {
+				// The value has been supplied with --flag=value
+				if next := context.Peek(); next.Type == TokenArg && next.Index == flagToken.Index {
+					context.Next()
+					defaultValue = next.Value
+				}
+			}
 		} else {
+			// The value of a flag accepting @file must not be expanded as arguments
+			context.literalNext = flag.allowFileValue
+			context.dashNext = flag.acceptsDash()
 			token = context.Peek()
+			context.literalNext, context.dashNext = false, false
 			if token.Type != TokenArg {
 				context.Push(token)
-				return nil, fmt.Errorf("expected argument for flag '%s'", flagToken)
+				return nil, context.errorf("expected argument for flag '%s'", flagToken)
 			}
 			context.Next()
 			defaultValue = token.Value
@@ -187,6 +202,16 @@ func (f *flagGroup) parse(context *ParseContext) (*FlagClause, error) {
 	}
 }
 
+// Returns true if the flag designated by a token belongs to the group.
+func (f *flagGroup) has(token *Token) bool {
+	if token.Type == TokenShort {
+		_, ok := f.short[token.Value]
+		return ok
+	}
+	flag, _, err := f.getFlagAlias(token.Value)
+	return err == nil && flag != nil
+}
+
 // FlagClause is a fluid interface used to build flags.
 type FlagClause struct {
 	parserMixin
@@ -194,13 +219,24 @@ type FlagClause struct {
 	completionsMixin
 	envarMixin
 	aliasMixin
+	deprecationMixin
 	name          string
 	shorthand     rune
 	help          string
+	helpLong      string
 	defaultValues []string
 	placeholder   string
 	hidden        bool
+	secret        bool
+	secretFile    *FlagClause // Companion flag used to read the secret from a file
+	secretOf      *FlagClause // Set if this flag is the companion of a secret flag
 	setByUser     *bool
+
+	allowFileValue bool
+	fileValueLimit int64
+	replacedBy     string
+	category       string
+	acceptsTopic   bool // Accepts a help topic with --flag=<topic>, see Application.HelpFlag
 }
 
 func newFlag(name, help string) *FlagClause {
@@ -211,14 +247,20 @@ func newFlag(name, help string) *FlagClause {
 	return f
 }
 
-func (f *FlagClause) setDefault() error {
+// Sets the environment or default value of the flag. The file of a
+// --<name>-file flag is read from stdin if its value is -.
+func (f *FlagClause) setDefault(stdin io.Reader) error {
+	setValue := f.setValue
+	if file, ok := f.value.(*secretFileValue); ok {
+		setValue = func(path string) error { return file.load(path, stdin) }
+	}
 	if f.HasEnvarValue() {
 		if v, ok := f.value.(repeatableFlag); !ok || !v.IsCumulative() {
 			// Use the value as-is
-			return f.value.Set(f.GetEnvarValue())
+			return setValue(f.GetEnvarValue())
 		}
 		for _, value := range f.GetSplitEnvarValue() {
-			if err := f.value.Set(value); err != nil {
+			if err := setValue(value); err != nil {
 				return err
 			}
 		}
@@ -227,7 +269,7 @@ func (f *FlagClause) setDefault() error {
 
 	if len(f.defaultValues) > 0 {
 		for _, defaultValue := range f.defaultValues {
-			if err := f.value.Set(defaultValue); err != nil {
+			if err := setValue(defaultValue); err != nil {
 				return err
 			}
 		}
@@ -279,6 +321,34 @@ func (f *FlagClause) PreAction(action Action) *FlagClause {
 	return f
 }
 
+// ActionCtx is like Action, but the callback also receives the context supplied
+// to Application.ParseWithContext.
+func (f *FlagClause) ActionCtx(action ActionCtx) *FlagClause {
+	return f.Action(WithContext(action))
+}
+
+// PreActionCtx is like PreAction, but the callback also receives the context
+// supplied to Application.ParseWithContext.
+func (f *FlagClause) PreActionCtx(action ActionCtx) *FlagClause {
+	return f.PreAction(WithContext(action))
+}
+
+// PostAction called after the actions, even if they failed, once the
+// pre-actions of the clause ran. Post actions are executed in the reverse
+// order of the command line, and in the reverse order of their registration
+// for a given clause.
+func (f *FlagClause) PostAction(action Action) *FlagClause {
+	f.addPostAction(action)
+	return f
+}
+
+// OnError called with the error returned by a pre-action or an action of the
+// flag, before the post actions.
+func (f *FlagClause) OnError(action ErrorAction) *FlagClause {
+	f.addErrorAction(action)
+	return f
+}
+
 // HintAction registers a HintAction (function) for the flag to provide completions.
 func (f *FlagClause) HintAction(action HintAction) *FlagClause {
 	if action == nil {
@@ -386,6 +456,13 @@ func (f *FlagClause) Help(help string) *FlagClause {
 	return f
 }
 
+// HelpLong adds a long help text, displayed by the long help, the man page
+// and the help of the flag (--help=<flag>) in addition to the help message.
+func (f *FlagClause) HelpLong(help string) *FlagClause {
+	f.helpLong = help
+	return f
+}
+
 // Bool makes this flag a boolean flag.
 func (f *FlagClause) Bool() (target *bool) {
 	target = new(bool)
@@ -0,0 +1,46 @@
+package kingpin
+
+// Freeze initializes the application and prevents ParseContext from modifying
+// the application definition. Once frozen, the application can be shared by
+// several goroutines calling ParseContext concurrently, as long as the model is
+// no longer modified. The unmanaged arguments are then only available through
+// ParseContext.UnmanagedArgs().
+//
+// Parse still binds the parsed values to their targets, so concurrent calls must
+// not set the same targets (see ParseResult). Calling Freeze several times
+// returns the result of the first call.
+func (a *Application) Freeze() error {
+	a.freezeOnce.Do(func() {
+		if a.freezeErr = a.init(); a.freezeErr != nil {
+			return
+		}
+		// The aliases and shortcuts are lazily computed during the parse, so
+		// we force their evaluation for every flag group before being frozen.
+		for _, group := range allFlagGroups(a.flagGroup, a.cmdGroup) {
+			for _, flag := range group.flagOrder {
+				if flag.autoShortcut == nil {
+					flag.autoShortcut = &group.autoShortcut
+				}
+			}
+			if a.freezeErr = group.resetAliases(); a.freezeErr != nil {
+				return
+			}
+		}
+		a.frozen = true
+	})
+	return a.freezeErr
+}
+
+// IsFrozen returns true if Freeze has been successfully called on the application.
+func (a *Application) IsFrozen() bool {
+	return a.frozen
+}
+
+// Returns the flag group and the flag groups of all its sub commands.
+func allFlagGroups(flags *flagGroup, cmds *cmdGroup) []*flagGroup {
+	groups := []*flagGroup{flags}
+	for _, cmd := range cmds.commandOrder {
+		groups = append(groups, allFlagGroups(cmd.flagGroup, cmd.cmdGroup)...)
+	}
+	return groups
+}
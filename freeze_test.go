@@ -0,0 +1,154 @@
+package kingpin
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezeConcurrentParseContext(t *testing.T) {
+	app := newTestApp().AutoShortcut().AllowUnmanaged()
+	app.Flag("verbose", "").Bool()
+	app.Flag("log-level", "").String()
+	server := app.Command("server", "")
+	server.Flag("port", "").Alias("listen").Int()
+	server.Command("start", "").Default()
+	server.Command("stop", "")
+	app.Command("client", "").Arg("url", "").String()
+	assert.NoError(t, app.Freeze())
+	assert.True(t, app.IsFrozen())
+
+	cases := []struct {
+		args     []string
+		selected string
+		elements int
+	}{
+		{[]string{"--verbose", "server", "--listen=8080"}, "server start", 2},
+		{[]string{"--no-verbose", "server", "stop", "--port", "80"}, "server stop", 2},
+		{[]string{"--ll=debug", "client", "http://host", "--unknown"}, "client", 2},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		c := cases[i%len(cases)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			context, err := app.ParseContext(c.args)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, c.selected, context.SelectedCommand.FullCommand())
+			var matched int
+			for _, element := range context.Elements {
+				if _, ok := element.Clause.(*CmdClause); !ok {
+					matched++
+				}
+			}
+			assert.Equal(t, c.elements, matched, fmt.Sprint(c.args))
+		}()
+	}
+	wg.Wait()
+	assert.Empty(t, app.Unmanaged)
+}
+
+type echoPrompter struct{}
+
+func (echoPrompter) Prompt(prompt *Prompt) (string, error) {
+	return strings.Trim(prompt.Name, "-<>"), nil
+}
+
+func TestFreezeConcurrentParse(t *testing.T) {
+	var errors syncBuffer
+	app := newTestApp().ErrorWriter(&errors).Prompter(echoPrompter{})
+	type target struct {
+		old, name *string
+	}
+	targets := make([]target, 20)
+	for i := range targets {
+		cmd := app.Command(fmt.Sprintf("cmd%d", i), "")
+		targets[i] = target{
+			old:  cmd.Flag("old", "").Deprecated("").String(),
+			name: cmd.Flag("name", "").Required().String(),
+		}
+	}
+	assert.NoError(t, app.Freeze())
+
+	var wg sync.WaitGroup
+	for i := range targets {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			command, err := app.Parse([]string{fmt.Sprintf("cmd%d", i), "--old=value"})
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("cmd%d", i), command)
+			assert.Equal(t, "value", *targets[i].old)
+			assert.Equal(t, "name", *targets[i].name)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, len(targets), strings.Count(errors.String(), "flag '--old' is deprecated"))
+}
+
+// Buffer which can be written concurrently.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestFreezeUnmanagedArgs(t *testing.T) {
+	app := newTestApp().AllowUnmanaged()
+	app.Flag("flag", "").String()
+	assert.NoError(t, app.Freeze())
+
+	context, err := app.ParseContext([]string{"--unknown", "--flag=value"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"--unknown"}, context.UnmanagedArgs())
+	assert.Empty(t, app.Unmanaged)
+
+	_, err = app.Parse([]string{"--unknown"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"--unknown"}, app.Unmanaged)
+}
+
+func TestFreezeError(t *testing.T) {
+	app := newTestApp()
+	app.Arg("arg", "").String()
+	app.Command("cmd", "")
+	assert.EqualError(t, app.Freeze(), "can't mix top-level Arg()s with Command()s")
+	assert.Error(t, app.Freeze())
+	assert.False(t, app.IsFrozen())
+}
+
+func TestFreezeKeepsSetByUser(t *testing.T) {
+	var set bool
+	app := newTestApp()
+	value := app.Flag("flag", "").IsSetByUser(&set).String()
+	assert.NoError(t, app.Freeze())
+
+	_, err := app.ParseContext([]string{"--flag=x"})
+	assert.NoError(t, err)
+	assert.False(t, set)
+
+	_, err = app.Parse([]string{"--flag=x"})
+	assert.NoError(t, err)
+	assert.True(t, set)
+	assert.Equal(t, "x", *value)
+}
@@ -14,7 +14,9 @@ var (
 	HelpCommand = CommandLine.HelpCommand
 	// VersionFlag is the global version flag. Exposed for user customisation. May be nil.
 	VersionFlag = CommandLine.VersionFlag
-	// EnableFileExpansion indicates Whether to file expansion with '@' is enabled.
+	// EnableFileExpansion indicates whether file expansion with '@' is enabled.
+	// It is used as the default value by applications that do not configure
+	// it with Application.FileExpansion().
 	EnableFileExpansion = true
 )
 
@@ -0,0 +1,216 @@
+package kingpin
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// HelpTopic registers a free-form help topic, displayed by "help <name>" or
+// "--help=<name>".
+func (a *Application) HelpTopic(name, text string) *Application {
+	if a.helpTopics == nil {
+		a.helpTopics = map[string]string{}
+	}
+	if _, ok := a.helpTopics[name]; !ok {
+		a.helpTopicOrder = append(a.helpTopicOrder, name)
+	}
+	a.helpTopics[name] = text
+	return a
+}
+
+// HelpTopicModel represents a help topic registered with HelpTopic().
+type HelpTopicModel struct {
+	Name string
+	Text string
+}
+
+func (a *Application) helpTopicsModel() []*HelpTopicModel {
+	var out []*HelpTopicModel
+	for _, name := range a.helpTopicOrder {
+		out = append(out, &HelpTopicModel{Name: name, Text: a.helpTopics[name]})
+	}
+	return out
+}
+
+// Returns the topic supplied to the help flag, empty if the value is a
+// boolean.
+func helpFlagTopic(value string) string {
+	if _, err := strconv.ParseBool(value); err == nil {
+		return ""
+	}
+	return value
+}
+
+// Returns the value to set to the flag: the help flag remains a boolean flag,
+// which is set by a help topic.
+func (f *FlagClause) valueWithoutTopic(value string) string {
+	if f.acceptsTopic && helpFlagTopic(value) != "" {
+		return "true"
+	}
+	return value
+}
+
+// Displays the help of the arguments supplied to the help command. The last
+// argument can be a flag of the designated command, or a help topic, and
+// "--search <term>" searches the whole command tree. The exit status is
+// returned.
+func (a *Application) helpCommand(args []string) int {
+	if len(args) > 0 && args[0] == "--search" {
+		return a.writeSearch(strings.Join(args[1:], " "))
+	}
+	topic := ""
+	if n := len(args); n > 0 && strings.HasPrefix(args[n-1], "-") {
+		args, topic = args[:n-1], args[n-1]
+	} else if n == 1 && a.GetCommand(args[0]) == nil {
+		if _, ok := a.helpTopics[args[0]]; ok {
+			args, topic = nil, args[0]
+		}
+	}
+	context, err := a.parseContext(true, args)
+	if err != nil {
+		a.Errorf("%s", err)
+		return 1
+	}
+	if topic != "" {
+		return a.writeHelpTopic(context, topic)
+	}
+	if err := a.withPager(func() error { return a.UsageForContext(context) }); err != nil {
+		a.Errorf("%s", err)
+		return 1
+	}
+	return 0
+}
+
+// Displays a help topic and returns the exit status.
+func (a *Application) writeHelpTopic(context *ParseContext, topic string) int {
+	if err := a.withPager(func() error { return a.helpTopic(context, topic) }); err != nil {
+		a.Errorf("%s", err)
+		return 1
+	}
+	return 0
+}
+
+type flagHelpContext struct {
+	App      *ApplicationModel
+	Flag     *FlagModel
+	Examples []*ExampleModel // Examples using the flag
+	Width    int
+}
+
+// Displays the description of a flag of the context, or a help topic.
+func (a *Application) helpTopic(context *ParseContext, topic string) error {
+	if flag := lookupHelpFlag(context.flags, topic); flag != nil {
+		model := flag.Model()
+		a.translateBuiltinHelp(model)
+		var examples []*ExampleModel
+		for _, example := range a.contextExamples(context) {
+			if exampleUsesFlag(example, model) {
+				examples = append(examples, example)
+			}
+		}
+		return a.executeTemplate(a.usageWriter, 2, FlagHelpTemplate, flagHelpContext{
+			App:      a.Model(),
+			Flag:     model,
+			Examples: examples,
+			Width:    guessWidth(a.displayWriter()),
+		})
+	}
+	if text, ok := a.helpTopics[strings.TrimLeft(topic, "-")]; ok {
+		return a.executeTemplate(a.usageWriter, 2, "{{.|Wrap 0}}", text)
+	}
+	return fmt.Errorf(a.translate("unknown help topic %q"), topic)
+}
+
+// Returns the flag designated by a topic such as timeout, --timeout or -t.
+func lookupHelpFlag(flags *flagGroup, topic string) *FlagClause {
+	var flag *FlagClause
+	var err error
+	if strings.HasPrefix(topic, "-") && !strings.HasPrefix(topic, "--") {
+		flag = flags.short[topic[1:]]
+	} else {
+		flag, _, err = flags.getFlagAlias(strings.TrimPrefix(topic, "--"))
+	}
+	if err != nil || flag == nil || flag.hidden {
+		return nil
+	}
+	return flag
+}
+
+// Returns the examples of the application and of the selected commands.
+func (a *Application) contextExamples(context *ParseContext) []*ExampleModel {
+	examples := examplesModel(a.Name, a.examples)
+	for _, element := range context.Elements {
+		if cmd, ok := element.Clause.(*CmdClause); ok {
+			examples = append(examples, examplesModel(a.Name, cmd.examples)...)
+		}
+	}
+	return examples
+}
+
+func exampleUsesFlag(example *ExampleModel, flag *FlagModel) bool {
+	names := []string{"--" + flag.Name, "--no-" + flag.Name}
+	for _, alias := range append(flag.Aliases, flag.NegativeAliases...) {
+		names = append(names, "--"+alias)
+	}
+	for _, word := range strings.Fields(example.Command) {
+		if flag.Short != 0 && strings.HasPrefix(word, "-"+string(flag.Short)) {
+			return true
+		}
+		for _, name := range names {
+			if word == name || strings.HasPrefix(word, name+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the rows describing the characteristics of a flag, translated with
+// the catalog.
+func flagDetails(flag *FlagModel, catalog MessageCatalog) [][2]string {
+	var rows [][2]string
+	add := func(name string, values ...string) {
+		if len(values) > 0 && values[0] != "" {
+			rows = append(rows, [2]string{translate(catalog, name) + ":", strings.Join(values, ", ")})
+		}
+	}
+	add("Type", valueTypeName(flag.Value))
+	if !flag.Secret {
+		add("Default", flag.Default...)
+	}
+	add("Environment", flag.Envar)
+	var aliases []string
+	for _, alias := range flag.Aliases {
+		aliases = append(aliases, "--"+alias)
+	}
+	for _, alias := range flag.NegativeAliases {
+		aliases = append(aliases, "--"+alias)
+	}
+	add("Aliases", aliases...)
+	var constraints []string
+	if flag.Required {
+		constraints = append(constraints, translate(catalog, "required"))
+	}
+	if v, ok := flag.Value.(repeatableFlag); ok && v.IsCumulative() {
+		constraints = append(constraints, translate(catalog, "repeatable"))
+	}
+	if e, ok := flag.Value.(Enumerable); ok {
+		constraints = append(constraints, fmt.Sprintf(translate(catalog, "one of %s"), strings.Join(e.Options(), ", ")))
+	}
+	add("Constraints", strings.Join(constraints, ", "))
+	if flag.Deprecated {
+		add("Deprecated", flag.DeprecationNote)
+	}
+	return rows
+}
+
+func valueTypeName(value Value) string {
+	if getter, ok := value.(Getter); ok {
+		if v := getter.Get(); v != nil {
+			return reflect.TypeOf(v).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newHelpTopicApp(buf *bytes.Buffer) *Application {
+	app := newTestApp().Writer(buf).ErrorWriter(buf).HelpTopic("glossary", "Node: a machine of the cluster.")
+	app.Terminate(func(status int) { panic(status) })
+	app.Flag("verbose", "Verbose mode.").Short('v').Bool()
+	deploy := app.Command("deploy", "Deploy the application.").
+		Example("deploy --format=json --timeout=1m", "Deploy with a timeout of one minute.").
+		Example("deploy --format=json --force", "Force the deployment.")
+	deploy.Flag("timeout", "Timeout of the deployment.").Default("30s").Envar("APP_TIMEOUT").Alias("wait").Duration()
+	deploy.Flag("force", "Force the deployment.").Bool()
+	deploy.Flag("format", "Output format.").Required().Enum("json", "yaml")
+	app.Command("status", "Show the status.")
+	return app
+}
+
+// Parses the arguments and returns the exit status, -1 if the application did
+// not terminate.
+func parseHelp(app *Application, args ...string) (status int) {
+	status = -1
+	defer func() {
+		if r := recover(); r != nil {
+			status = r.(int)
+		}
+	}()
+	app.Parse(args)
+	return
+}
+
+func TestHelpFlagTopic(t *testing.T) {
+	var buf bytes.Buffer
+	app := newHelpTopicApp(&buf)
+	assert.Equal(t, 0, parseHelp(app, "deploy", "--help=timeout"))
+	assert.Equal(t, ""+
+		"--timeout=30s\n"+
+		"\n"+
+		"  Timeout of the deployment.\n"+
+		"\n"+
+		"  Type:         time.Duration\n"+
+		"  Default:      30s\n"+
+		"  Environment:  APP_TIMEOUT\n"+
+		"  Aliases:      --wait\n"+
+		"\n"+
+		"Examples:\n"+
+		"  test deploy --format=json --timeout=1m\n"+
+		"    Deploy with a timeout of one minute.\n"+
+		"\n", buf.String())
+
+	buf.Reset()
+	app = newHelpTopicApp(&buf)
+	assert.Equal(t, 0, parseHelp(app, "help", "deploy", "--format"))
+	assert.Contains(t, buf.String(), "--format=FORMAT\n")
+	assert.Contains(t, buf.String(), "Constraints:  required, one of json, yaml\n")
+	assert.Contains(t, buf.String(), "Force the deployment.\n")
+
+	buf.Reset()
+	app = newHelpTopicApp(&buf)
+	assert.Equal(t, 0, parseHelp(app, "help", "deploy", "--wait"))
+	assert.Contains(t, buf.String(), "--timeout=30s\n")
+
+	buf.Reset()
+	assert.Equal(t, 0, parseHelp(app, "--help=-v"))
+	assert.Contains(t, buf.String(), "-v, --[no-]verbose\n")
+
+	buf.Reset()
+	assert.Equal(t, 1, parseHelp(app, "status", "--help=timeout"))
+	assert.Equal(t, "test: error: unknown help topic \"timeout\"\n", buf.String())
+
+	buf.Reset()
+	assert.Equal(t, 0, parseHelp(app, "deploy", "--help=true"))
+	assert.Contains(t, buf.String(), "usage: test deploy")
+}
+
+func TestHelpFlagIsBool(t *testing.T) {
+	var buf bytes.Buffer
+	app := newHelpTopicApp(&buf)
+	assert.True(t, app.HelpFlag.Model().IsBoolFlag())
+	assert.Equal(t, false, app.HelpFlag.Model().Value.(Getter).Get())
+
+	help := false
+	app.HelpFlag.BoolVar(&help)
+	assert.Equal(t, 0, parseHelp(app, "deploy", "--help=timeout"), "the topics work with a replaced value")
+	assert.True(t, help)
+	assert.Contains(t, buf.String(), "Timeout of the deployment.")
+}
+
+func TestHelpTopic(t *testing.T) {
+	var buf bytes.Buffer
+	app := newHelpTopicApp(&buf)
+	assert.Equal(t, 0, parseHelp(app, "help", "glossary"))
+	assert.Equal(t, "Node: a machine of the cluster.\n", buf.String())
+
+	buf.Reset()
+	assert.Equal(t, 0, parseHelp(app, "--help=glossary"))
+	assert.Equal(t, "Node: a machine of the cluster.\n", buf.String())
+
+	buf.Reset()
+	app = newHelpTopicApp(&buf)
+	assert.Equal(t, 0, parseHelp(app, "help", "status"))
+	assert.Contains(t, buf.String(), "usage: test status")
+
+	assert.Equal(t, []*HelpTopicModel{{Name: "glossary", Text: "Node: a machine of the cluster."}}, app.Model().HelpTopics)
+}
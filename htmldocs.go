@@ -0,0 +1,153 @@
+package kingpin
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+)
+
+// HTMLDocsTemplate specifies the html/template used by WriteHTMLDocs. It must
+// define the "index" and "command" templates, rendering respectively the index
+// page and the page of a command. The default is DefaultHTMLDocsTemplate.
+func (a *Application) HTMLDocsTemplate(template string) *Application {
+	a.htmlDocsTemplate = template
+	return a
+}
+
+type htmlDocsContext struct {
+	App      *ApplicationModel
+	Command  *CmdModel   // Command of the page, nil for the index
+	Parents  []*CmdModel // Parent commands of Command, whose flags also apply
+	Commands []*CmdModel // Visible commands having a page
+	Title    string
+}
+
+// Entry of the search index (search.json) of the HTML documentation.
+type htmlSearchEntry struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+// WriteHTMLDocs writes the HTML documentation of the application to dir, which
+// is created if needed: an index page, a page for each visible command, and the
+// search index search.json.
+func (a *Application) WriteHTMLDocs(dir string) error {
+	if err := a.init(); err != nil {
+		return err
+	}
+	funcs := htmltemplate.FuncMap{
+		"PageURL": func(cmd *CmdModel) string {
+			return htmlPageURL(a.Name, cmd)
+		},
+		"FlagAnchor": htmlFlagAnchor,
+		"T":          a.translate,
+		"FormatFlag": func(flag *FlagModel) string {
+			return formatFlag(flag.Short != 0, flag)
+		},
+		"FormatArg":          formatArg,
+		"FormatAppUsage":     formatAppUsage,
+		"FormatCommandUsage": formatCmdUsage,
+		"AnnotatedHelp": func(model interface {
+			annotatedHelp(Annotations, MessageCatalog) string
+		}) string {
+			return model.annotatedHelp(AnnotateAll, a.messages)
+		},
+		"DeprecationNotice": func(model interface{}) string {
+			return modelDeprecationNotice(model, a.messages)
+		},
+	}
+	for k, v := range a.usageFuncs {
+		funcs[k] = v
+	}
+	tmpl, err := htmltemplate.New("docs").Funcs(funcs).Parse(a.htmlDocsTemplate)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	model := a.Model()
+	ctx := htmlDocsContext{App: model, Commands: manPageCommands(model.Commands), Title: model.Name}
+	if err := writeHTMLPage(dir, htmlPageURL(a.Name, nil), tmpl, "index", ctx); err != nil {
+		return err
+	}
+	index := htmlSearchIndex(model.Name, model.Flags, nil)
+	var walk func(cmds []*CmdModel, parents []*CmdModel) error
+	walk = func(cmds []*CmdModel, parents []*CmdModel) error {
+		for _, cmd := range cmds {
+			if cmd.Hidden {
+				continue
+			}
+			ctx.Command, ctx.Parents, ctx.Title = cmd, parents, model.Name+" "+cmd.FullCommand
+			if err := writeHTMLPage(dir, htmlPageURL(a.Name, cmd), tmpl, "command", ctx); err != nil {
+				return err
+			}
+			index = append(index, htmlSearchIndex(model.Name, cmd.Flags, cmd)...)
+			if err := walk(cmd.Commands, append(parents[:len(parents):len(parents)], cmd)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(model.Commands, nil); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "search.json"), append(data, '\n'), 0644)
+}
+
+func writeHTMLPage(dir, name string, tmpl *htmltemplate.Template, page string, data htmlDocsContext) error {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = tmpl.ExecuteTemplate(f, page, data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("can't write documentation page %s: %s", path, err)
+	}
+	return nil
+}
+
+// Returns the name of the page of a command, or of the index if cmd is nil.
+func htmlPageURL(app string, cmd *CmdModel) string {
+	if cmd == nil {
+		return "index.html"
+	}
+	return manPageName(app, cmd) + ".html"
+}
+
+func htmlFlagAnchor(flag *FlagModel) string {
+	return "flag-" + flag.Name
+}
+
+// Returns the search entries of a command and of its visible flags, or of the
+// flags of the application if cmd is nil.
+func htmlSearchIndex(app string, flags []*FlagModel, cmd *CmdModel) []*htmlSearchEntry {
+	var entries []*htmlSearchEntry
+	url, prefix := htmlPageURL(app, cmd), ""
+	if cmd != nil {
+		entries = append(entries, &htmlSearchEntry{Title: cmd.FullCommand, URL: url, Text: cmd.Help})
+		prefix = cmd.FullCommand + " "
+	}
+	for _, flag := range flags {
+		if !flag.Hidden {
+			entries = append(entries, &htmlSearchEntry{
+				Title: prefix + "--" + flag.Name,
+				URL:   url + "#" + htmlFlagAnchor(flag),
+				Text:  flag.Help,
+			})
+		}
+	}
+	return entries
+}
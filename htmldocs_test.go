@@ -0,0 +1,92 @@
+package kingpin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTMLDocs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "docs")
+	app := newTestApp()
+	app.Help = "Manage <nodes>."
+	app.Flag("server", "Server.").Envar("TEST_SERVER").String()
+	nodes := app.Command("nodes", "Manage the nodes.")
+	nodes.Flag("cluster", "Cluster.").String()
+	add := nodes.Command("add", "Add a node.").Example("nodes add n1", "Add n1.")
+	add.Flag("zone", "Zone.").Short('z').Default("eu").String()
+	add.Arg("name", "Name.").Required().String()
+	nodes.Command("remove", "Remove a node.").Hidden()
+	app.Command("internal", "Internal.").Hidden().Command("debug", "Debug.")
+	assert.NoError(t, app.WriteHTMLDocs(dir))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	assert.ElementsMatch(t, []string{"index.html", "search.json", "test-help.html", "test-nodes.html", "test-nodes-add.html"}, names)
+
+	page, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<html lang=\"en\">")
+	assert.Contains(t, string(page), "<p class=\"long\">Manage &lt;nodes&gt;.</p>")
+	assert.Contains(t, string(page), "<tr id=\"flag-server\">\n<td><code>--server=SERVER</code></td>\n<td>Server. [env: TEST_SERVER]</td>")
+	assert.Contains(t, string(page), "<a href=\"test-nodes-add.html\"><code>nodes add</code></a>")
+	assert.NotContains(t, string(page), "remove")
+	assert.NotContains(t, string(page), "debug")
+
+	page, err = os.ReadFile(filepath.Join(dir, "test-nodes.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<h2 id=\"flags\">Flags</h2>\n<table>\n<tr id=\"flag-cluster\">")
+	assert.Contains(t, string(page), "<h2>Subcommands</h2>\n<table>\n<tr><td><a href=\"test-nodes-add.html\"><code>nodes add</code></a></td><td>Add a node.</td></tr>\n</table>")
+
+	page, err = os.ReadFile(filepath.Join(dir, "test-nodes-add.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<h1>test nodes add</h1>")
+	assert.Contains(t, string(page), "<tr id=\"flag-zone\">\n<td><code>-z, --zone=&#34;eu&#34;</code></td>\n<td>Zone. [default: eu]</td>")
+	assert.Contains(t, string(page), "<td><code>&lt;name&gt;</code></td>")
+	assert.Contains(t, string(page), "<dt><code>test nodes add n1</code></dt>\n<dd>Add n1.</dd>")
+	assert.Contains(t, string(page), "<p><a href=\"test-nodes.html#flags\">Flags of nodes</a></p>")
+
+	data, err := os.ReadFile(filepath.Join(dir, "search.json"))
+	assert.NoError(t, err)
+	var index []*htmlSearchEntry
+	assert.NoError(t, json.Unmarshal(data, &index))
+	assert.Contains(t, index, &htmlSearchEntry{Title: "--server", URL: "index.html#flag-server", Text: "Server."})
+	assert.Contains(t, index, &htmlSearchEntry{Title: "nodes add", URL: "test-nodes-add.html", Text: "Add a node."})
+	assert.Contains(t, index, &htmlSearchEntry{Title: "nodes add --zone", URL: "test-nodes-add.html#flag-zone", Text: "Zone."})
+}
+
+func TestHTMLDocsLanguage(t *testing.T) {
+	dir := t.TempDir()
+	app := newTestApp().Messages(frenchMessages)
+	app.Flag("server", "Server.").String()
+	assert.NoError(t, app.WriteHTMLDocs(dir))
+	page, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<html lang=\"fr\">")
+	assert.Contains(t, string(page), "<h2 id=\"flags\">Options</h2>")
+}
+
+func TestHTMLDocsTemplate(t *testing.T) {
+	dir := t.TempDir()
+	app := newTestApp().HTMLDocsTemplate(`{{define "index"}}{{.Title}}: {{len .Commands}}{{end}}{{define "command"}}{{.Title}}{{end}}`)
+	app.Command("status", "")
+	assert.NoError(t, app.WriteHTMLDocs(dir))
+
+	page, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(t, err)
+	assert.Equal(t, "test: 2", string(page))
+	page, err = os.ReadFile(filepath.Join(dir, "test-status.html"))
+	assert.NoError(t, err)
+	assert.Equal(t, "test status", string(page))
+
+	app = newTestApp().HTMLDocsTemplate(`{{define "index"}}{{end}}`)
+	app.Command("status", "")
+	assert.Error(t, app.WriteHTMLDocs(dir))
+}
@@ -0,0 +1,50 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelpLong(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf)
+	app.Flag("timeout", "Timeout.").HelpLong("The maximum duration of the operation.").Duration()
+	deploy := app.Command("deploy", "Deploy.")
+	deploy.Flag("force", "Force.").HelpLong("Skip the confirmations.").Bool()
+	deploy.Arg("target", "Target.").HelpLong("The environment to deploy to.").String()
+
+	assert.Equal(t, "The maximum duration of the operation.", app.GetFlag("timeout").Model().HelpLong)
+	assert.Equal(t, "The environment to deploy to.", deploy.GetArg("target").Model().HelpLong)
+
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "  --timeout=TIMEOUT  Timeout.\n")
+	assert.NotContains(t, buf.String(), "The maximum duration")
+
+	buf.Reset()
+	app.UsageTemplate(LongHelpTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), "  --timeout=TIMEOUT  Timeout.\n")
+	assert.Contains(t, buf.String(), "\n  --timeout=TIMEOUT\n      The maximum duration of the operation.\n")
+	assert.Contains(t, buf.String(), "\n    --[no-]force\n        Skip the confirmations.\n")
+
+	buf.Reset()
+	app.UsageTemplate(DefaultUsageTemplate).Usage([]string{"deploy"})
+	assert.NotContains(t, buf.String(), "The environment to deploy to.")
+
+	buf.Reset()
+	app.UsageTemplate(ManPageTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), "Timeout.\n.IP\nThe maximum duration of the operation.\n")
+
+	buf.Reset()
+	assert.NoError(t, app.helpTopic(&ParseContext{flags: app.flagGroup}, "timeout"))
+	assert.Contains(t, buf.String(), "  Timeout.\n\n  The maximum duration of the operation.\n\n  Type:")
+}
+
+func TestArgHelpLong(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).UsageTemplate(LongHelpTemplate)
+	app.Arg("target", "Target.").HelpLong("The environment to deploy to.").String()
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "  [<target>]  Target.\n\n  [<target>]\n      The environment to deploy to.\n")
+}
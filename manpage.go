@@ -0,0 +1,153 @@
+package kingpin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ManSection sets the section of the man pages, 1 by default.
+func (a *Application) ManSection(section string) *Application {
+	a.manSection = section
+	return a
+}
+
+// ManDate sets the date displayed in the footer of the man pages, such as
+// "January 2006".
+func (a *Application) ManDate(date string) *Application {
+	a.manDate = date
+	return a
+}
+
+// WriteManPages writes the man page of the application (<app>.1) and a page
+// for each visible command (<app>-<command>-<sub command>.1) to dir, which is
+// created if needed.
+func (a *Application) WriteManPages(dir string) error {
+	if err := a.init(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	appTemplate, err := a.parseTemplate(2, ManPageTemplate)
+	if err != nil {
+		return err
+	}
+	cmdTemplate, err := a.parseTemplate(2, ManCommandPageTemplate)
+	if err != nil {
+		return err
+	}
+	model := a.Model()
+	err = writeManPage(dir, a.Name, appTemplate, templateContext{
+		App:      model,
+		ManPages: true,
+		Context: &templateParseContext{
+			FlagGroupModel: model.FlagGroupModel,
+			ArgGroupModel:  model.ArgGroupModel,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var walk func(cmds []*CmdClause) error
+	walk = func(cmds []*CmdClause) error {
+		for _, cmd := range cmds {
+			if cmd.hidden {
+				continue
+			}
+			cmdModel := cmd.Model()
+			err := writeManPage(dir, manPageName(a.Name, cmdModel), cmdTemplate, templateContext{
+				App:      model,
+				ManPages: true,
+				Context: &templateParseContext{
+					SelectedCommand: cmdModel,
+					FlagGroupModel:  cmdModel.FlagGroupModel,
+					ArgGroupModel:   cmdModel.ArgGroupModel,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if err := walk(cmd.commandOrder); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(a.commandOrder)
+}
+
+func writeManPage(dir, name string, tmpl *template.Template, data templateContext) error {
+	path := filepath.Join(dir, name+"."+data.App.ManSection)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = tmpl.Execute(f, data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("can't write man page %s: %s", path, err)
+	}
+	return nil
+}
+
+// Returns the name of the man page of a command, or of the application if cmd
+// is nil.
+func manPageName(app string, cmd *CmdModel) string {
+	if cmd == nil {
+		return app
+	}
+	return app + "-" + strings.Join(strings.Fields(cmd.FullCommand), "-")
+}
+
+// Returns the visible commands and sub commands.
+func manPageCommands(cmds []*CmdModel) []*CmdModel {
+	var out []*CmdModel
+	for _, cmd := range cmds {
+		if !cmd.Hidden {
+			out = append(out, cmd)
+			out = append(out, manPageCommands(cmd.Commands)...)
+		}
+	}
+	return out
+}
+
+// Returns the visible flags of the commands and their sub commands having an
+// environment variable, once per variable.
+func environmentFlags(flags []*FlagModel, cmds []*CmdModel) []*FlagModel {
+	var out []*FlagModel
+	seen := map[string]bool{}
+	var add func(flags []*FlagModel, cmds []*CmdModel)
+	add = func(flags []*FlagModel, cmds []*CmdModel) {
+		for _, flag := range flags {
+			if flag.Envar != "" && !flag.Hidden && !seen[flag.Envar] {
+				seen[flag.Envar] = true
+				out = append(out, flag)
+			}
+		}
+		for _, cmd := range cmds {
+			if !cmd.Hidden {
+				add(cmd.Flags, cmd.Commands)
+			}
+		}
+	}
+	add(flags, cmds)
+	return out
+}
+
+var roffReplacer = strings.NewReplacer(`\`, `\e`, "\n.", "\n\\&.", "\n'", "\n\\&'")
+
+// Escapes the characters of a text having a special meaning in roff: the
+// backslashes, and the dots and quotes starting a line, which would be read as
+// requests.
+func roffEscape(s string) string {
+	s = roffReplacer.Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
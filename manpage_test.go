@@ -0,0 +1,98 @@
+package kingpin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoffEscape(t *testing.T) {
+	assert.Equal(t, `a\eb`, roffEscape(`a\b`))
+	assert.Equal(t, "\\&.start\n\\&.next\n\\&'quote\nend.", roffEscape(".start\n.next\n'quote\nend."))
+}
+
+func TestManPageSections(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).Version("1.0").Author("Alec").ManSection("8").ManDate("October 2026")
+	app.Help = "Manage\n.nodes"
+	app.Flag("server", "Server.").Envar("TEST_SERVER").String()
+	nodes := app.Command("nodes", "Manage the nodes.")
+	nodes.Command("add", "Add a node.").Flag("zone", `Zone (\ separated).`).Envar("TEST_ZONE").String()
+
+	app.UsageTemplate(ManPageTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), ".TH test 8 \"October 2026\" \"test 1.0\" \"Alec\"\n")
+	assert.Contains(t, buf.String(), ".SH \"DESCRIPTION\"\nManage\n\\&.nodes\n")
+	assert.Contains(t, buf.String(), ".SH \"ENVIRONMENT\"\n.TP\n\\fBTEST_SERVER\\fR\nValue of \\fB--server\\fR. Server.\n.TP\n\\fBTEST_ZONE\\fR\n")
+	assert.Contains(t, buf.String(), "Zone (\\e separated).\n")
+	assert.Contains(t, buf.String(), ".SH \"EXIT STATUS\"\n")
+	assert.NotContains(t, buf.String(), "SEE ALSO")
+}
+
+func TestWriteManPages(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "man")
+	app := newTestApp().ManDate("October 2026")
+	app.Flag("server", "Server.").String()
+	nodes := app.Command("nodes", "Manage the nodes.")
+	nodes.Command("add", "Add a node.").Example("nodes add", "Add a node.").Flag("zone", "Zone.").String()
+	nodes.Command("remove", "Remove a node.").Hidden()
+	app.Command("status", "Show the status.")
+	assert.NoError(t, app.WriteManPages(dir))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	sort.Strings(files)
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	assert.Equal(t, []string{"test-help.1", "test-nodes-add.1", "test-nodes.1", "test-status.1", "test.1"}, names)
+
+	page, err := os.ReadFile(filepath.Join(dir, "test.1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), ".SH \"SEE ALSO\"\n\\fBtest-help\\fR(1),\n\\fBtest-nodes\\fR(1),\n\\fBtest-nodes-add\\fR(1),\n\\fBtest-status\\fR(1)\n")
+
+	page, err = os.ReadFile(filepath.Join(dir, "test-nodes-add.1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), ".TH test-nodes-add 1 \"October 2026\" \"test\" \"\"\n")
+	assert.Contains(t, string(page), ".SH \"NAME\"\ntest-nodes-add \\- Add a node.\n")
+	assert.Contains(t, string(page), ".SH \"OPTIONS\"\n.TP\n\\fB--zone=ZONE\\fR\nZone.\n")
+	assert.Contains(t, string(page), ".SH \"GLOBAL OPTIONS\"\n")
+	assert.Contains(t, string(page), ".SH \"EXAMPLES\"\n.TP\n\\fBtest nodes add\\fR\nAdd a node.\n")
+	assert.Contains(t, string(page), ".SH \"SEE ALSO\"\n\\fBtest\\fR(1)\n")
+	assert.NotContains(t, string(page), "remove")
+}
+
+func TestManPageArguments(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf)
+	app.Arg("source", "Source file.").HelpLong("The file is read\nentirely.").String()
+	app.Arg("internal", "Internal.").Hidden().String()
+	app.Arg("targets", "Target files.").Strings()
+
+	app.UsageTemplate(ManPageTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), ".SH \"ARGUMENTS\"\n.TP\n\\fB<source>\\fR\nSource file.\n.IP\nThe file is read\nentirely.\n.TP\n\\fB<targets>...\\fR\nTarget files.\n")
+	assert.NotContains(t, buf.String(), "Internal.")
+
+	dir := t.TempDir()
+	app = newTestApp()
+	app.Command("copy", "Copy a file.").Arg("source", "Source file.").String()
+	assert.NoError(t, app.WriteManPages(dir))
+	page, err := os.ReadFile(filepath.Join(dir, "test-copy.1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), ".SH \"ARGUMENTS\"\n.TP\n\\fB<source>\\fR\nSource file.\n")
+}
+
+func TestManPageEscapesNames(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf)
+	app.Flag("path", "Path.").Envar(`TEST\PATH`).String()
+	app.Command(`back\slash`, "Command.")
+
+	app.UsageTemplate(ManPageTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), "\\fBTEST\\ePATH\\fR\n")
+	assert.Contains(t, buf.String(), ".SS\n\\fBback\\eslash\\fR\n")
+}
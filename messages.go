@@ -0,0 +1,125 @@
+package kingpin
+
+import (
+	"fmt"
+)
+
+// MessageCatalog translates the messages of the library: the parse errors,
+// the headings of the built-in templates and the help of the built-in flags.
+// The messages are identified by their English text, which is a format string
+// for the errors. Headings and labels are identified without their trailing
+// colon, such as "usage" or "Args". The translation of "en" is the language
+// tag of the catalog, used by the HTML documentation.
+type MessageCatalog interface {
+	// Message returns the translation of the English message, or the message
+	// itself if there is none.
+	Message(message string) string
+}
+
+// Translations is a MessageCatalog mapping English messages to their
+// translation, such as Translations{"Flags": "Options"}.
+type Translations map[string]string
+
+// Message returns the translation of the message, or the message itself if it
+// is not translated.
+func (t Translations) Message(message string) string {
+	if translation, ok := t[message]; ok {
+		return translation
+	}
+	return message
+}
+
+// EnglishMessages is the default catalog, leaving the messages unchanged.
+var EnglishMessages MessageCatalog = Translations{}
+
+// Help of the built-in flags, translated when the usage is rendered.
+var builtinFlagHelp = map[string]string{
+	"help":                   "Show context-sensitive help (also try --help-long and --help-man).",
+	"help-long":              "Generate long help.",
+	"help-man":               "Generate a man page.",
+	"completion-bash":        "Output possible completions for the given args.",
+	"completion-script-bash": "Generate completion script for bash.",
+	"completion-script-zsh":  "Generate completion script for ZSH.",
+	"version":                "Show application version.",
+}
+
+// Messages sets the catalog translating the messages of the library, which
+// is also available to the templates with the T function.
+// A nil catalog restores the built-in messages.
+func (a *Application) Messages(catalog MessageCatalog) *Application {
+	if catalog == nil {
+		catalog = EnglishMessages
+	}
+	a.messages = catalog
+	return a
+}
+
+// Translates the help of the built-in flags of the models, unless it has been
+// customised.
+func (a *Application) translateBuiltinHelp(flags ...*FlagModel) {
+	for _, flag := range flags {
+		if help, ok := builtinFlagHelp[flag.Name]; ok && flag.Help == help {
+			flag.Help = a.translate(help)
+		}
+	}
+}
+
+func translate(catalog MessageCatalog, message string) string {
+	if catalog == nil {
+		return message
+	}
+	return catalog.Message(message)
+}
+
+func (a *Application) translate(message string) string {
+	return translate(a.messages, message)
+}
+
+// Returns an error formatted with the translation of the format.
+func (p *ParseContext) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(translate(p.messages, format), args...)
+}
+
+// Error of the values, which have no access to the catalog: it is translated
+// by translateError once returned to the application.
+type translatableError struct {
+	format string
+	args   []interface{}
+}
+
+func translatableErrorf(format string, args ...interface{}) error {
+	return &translatableError{format: format, args: args}
+}
+
+func (e *translatableError) Error() string {
+	return fmt.Sprintf(e.format, e.args...)
+}
+
+// Translated error wrapping the original error, such as
+// ErrCommandNotSpecified, which can still be identified with errors.Is.
+type translatedError struct {
+	message string
+	err     error
+}
+
+func (e *translatedError) Error() string { return e.message }
+func (e *translatedError) Unwrap() error { return e.err }
+
+// Returns the translation of err if it is a translatable error or a sentinel
+// error of the package, err otherwise.
+func translateError(catalog MessageCatalog, err error) error {
+	switch e := err.(type) {
+	case *translatableError:
+		return fmt.Errorf(translate(catalog, e.format), e.args...)
+	case *secretError:
+		return &translatedError{message: fmt.Sprintf(translate(catalog, secretErrorFormat), secretMask, e.flag), err: e.err}
+	case nil:
+		return nil
+	}
+	if err == ErrCommandNotSpecified {
+		if message := translate(catalog, err.Error()); message != err.Error() {
+			return &translatedError{message: message, err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package kingpin
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var frenchMessages = Translations{
+	"en":                                     "fr",
+	"usage":                                  "utilisation",
+	"Flags":                                  "Options",
+	"Args":                                   "Arguments",
+	"error":                                  "erreur",
+	"warning":                                "avertissement",
+	"required flag(s) %s not provided":       "option(s) obligatoire(s) %s non fournie(s)",
+	"expected command but got %q":            "commande attendue au lieu de %q",
+	"unknown long flag '%s'":                 "option longue inconnue '%s'",
+	"flag '%s' cannot be repeated":           "l'option '%s' ne peut pas être répétée",
+	"must select a subcommand of '%s'":       "une sous-commande de '%s' est requise",
+	"command not specified":                  "commande non spécifiée",
+	"enum value must be one of %s, got '%s'": "la valeur doit être parmi %s, et non '%s'",
+	"flag":                                   "l'option",
+	"%s %s is deprecated":                    "%s %s est obsolète",
+	"(deprecated)":                           "(obsolète)",
+	"[default: %s]":                          "[défaut : %s]",
+	"[choices: %s]":                          "[choix : %s]",
+	"[env: %s]":                              "[env : %s]",
+	"Choice":                                 "Choix",
+	"no command or flag matches %q":          "aucune commande ou option ne correspond à %q",
+	"unknown help topic %q":                  "sujet d'aide inconnu %q",
+	"Show application version.":              "Affiche la version de l'application.",
+	"Show help.":                             "Affiche l'aide.",
+	builtinFlagHelp["help"]:                  "Affiche l'aide contextuelle.",
+}
+
+func TestTranslations(t *testing.T) {
+	assert.Equal(t, "Options", frenchMessages.Message("Flags"))
+	assert.Equal(t, "Commands", frenchMessages.Message("Commands"))
+	assert.Equal(t, "Flags", EnglishMessages.Message("Flags"))
+}
+
+func TestMessagesErrors(t *testing.T) {
+	app := newTestApp().Messages(frenchMessages)
+	app.Flag("name", "").Required().String()
+	_, err := app.Parse(nil)
+	assert.EqualError(t, err, "option(s) obligatoire(s) '--name' non fournie(s)")
+	_, err = app.Parse([]string{"--unknown"})
+	assert.EqualError(t, err, "option longue inconnue '--unknown'")
+
+	app = newTestApp().Messages(frenchMessages)
+	app.Command("status", "")
+	_, err = app.Parse([]string{"statut"})
+	assert.EqualError(t, err, `commande attendue au lieu de "statut"`)
+
+	var buf bytes.Buffer
+	app.ErrorWriter(&buf).Errorf("%s", "oups")
+	assert.Equal(t, "test: erreur: oups\n", buf.String())
+
+	app = newTestApp().Messages(frenchMessages)
+	app.Flag("name", "").String()
+	app.Flag("format", "").Enum("json", "yaml")
+	app.Command("nodes", "").Command("add", "")
+	_, err = app.Parse([]string{"--name=a", "--name=b", "nodes", "add"})
+	assert.EqualError(t, err, "l'option 'name' ne peut pas être répétée")
+	_, err = app.Parse([]string{"nodes"})
+	assert.EqualError(t, err, "une sous-commande de 'nodes' est requise")
+	_, err = app.Parse([]string{"--format=xml", "nodes", "add"})
+	assert.EqualError(t, err, "la valeur doit être parmi json,yaml, et non 'xml'")
+	_, err = app.ParseResult([]string{"--format=xml", "nodes", "add"})
+	assert.EqualError(t, err, "la valeur doit être parmi json,yaml, et non 'xml'")
+	_, err = app.ParseResult(nil)
+	assert.EqualError(t, err, "commande non spécifiée")
+	assert.True(t, errors.Is(err, ErrCommandNotSpecified))
+}
+
+func TestMessagesHelp(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).ErrorWriter(&buf).Messages(frenchMessages).Annotate(AnnotateAll)
+	app.Terminate(func(status int) { panic(status) })
+	app.Flag("format", "Format.").Default("json").Envar("TEST_FORMAT").Enum("json", "yaml")
+	app.Flag("old", "Old.").Deprecated("").Bool()
+	app.Command("status", "")
+
+	_, err := app.Parse([]string{"--old", "status"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test: avertissement: l'option '--old' est obsolète\n", buf.String())
+
+	buf.Reset()
+	app.UsageTemplate(LongHelpTemplate).Usage(nil)
+	assert.Contains(t, buf.String(), "Format. [défaut : json] [choix : json, yaml] [env :")
+	assert.Contains(t, buf.String(), "Old. (obsolète)")
+
+	buf.Reset()
+	assert.Equal(t, 1, parseHelp(app, "help", "--search", "unknown"))
+	assert.Equal(t, "test: erreur: aucune commande ou option ne correspond à \"unknown\"\n", buf.String())
+	assert.EqualError(t, app.helpTopic(&ParseContext{flags: app.flagGroup}, "unknown"), `sujet d'aide inconnu "unknown"`)
+}
+
+func TestMessagesUsage(t *testing.T) {
+	var buf bytes.Buffer
+	app := New("test", "").Terminate(nil).Writer(&buf).Version("1.0").Messages(frenchMessages)
+	app.Flag("name", "Name.").String()
+	app.Command("status", "")
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "utilisation: test [<flags>] <command>")
+	assert.Contains(t, buf.String(), "Options:\n")
+	assert.Contains(t, buf.String(), "--[no-]help     Affiche l'aide contextuelle.\n")
+	assert.Contains(t, buf.String(), "--[no-]version  Affiche la version de l'application.\n")
+	assert.Contains(t, buf.String(), "Commands:\n")
+	assert.Contains(t, buf.String(), "help [<command>...]\n    Affiche l'aide.\n")
+
+	buf.Reset()
+	assert.NoError(t, app.helpTopic(&ParseContext{flags: app.flagGroup}, "name"))
+	assert.Contains(t, buf.String(), "Type:")
+}
+
+func TestMessagesKeepCustomHelp(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).Messages(frenchMessages)
+	app.HelpFlag.Help("Custom help.")
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "--[no-]help  Custom help.\n")
+	assert.Equal(t, "Custom help.", app.HelpFlag.help)
+
+	buf.Reset()
+	app = newTestApp().Writer(&buf).Messages(frenchMessages)
+	assert.NoError(t, app.helpTopic(&ParseContext{flags: app.flagGroup}, "help"))
+	assert.Contains(t, buf.String(), "Affiche l'aide contextuelle.")
+	assert.Equal(t, builtinFlagHelp["help"], app.HelpFlag.help)
+
+	buf.Reset()
+	app.Messages(EnglishMessages).Usage(nil)
+	assert.Contains(t, buf.String(), "--[no-]help  Show context-sensitive help")
+}
+
+func TestMessagesNilCatalog(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).Messages(frenchMessages).Messages(nil)
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "usage: test\n")
+	assert.Contains(t, buf.String(), "--[no-]help  Show context-sensitive help")
+	assert.Equal(t, "Flags", app.translate("Flags"))
+}
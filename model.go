@@ -53,6 +53,7 @@ func (f *FlagGroupModel) FlagSummary() string {
 type FlagModel struct {
 	Name            string
 	Help            string
+	HelpLong        string
 	Short           rune
 	Default         []string
 	Envar           string
@@ -61,6 +62,11 @@ type FlagModel struct {
 	PlaceHolder     string
 	Required        bool
 	Hidden          bool
+	Secret          bool
+	Deprecated      bool
+	DeprecationNote string // Message supplied to Deprecated()
+	ReplacedBy      string
+	Category        string
 	Value           Value
 }
 
@@ -68,9 +74,17 @@ func (f *FlagModel) String() string {
 	if f.Value == nil {
 		return ""
 	}
+	if f.Secret {
+		return secretMask
+	}
 	return f.Value.String()
 }
 
+// DeprecationNotice returns the notice displayed in the help of a deprecated flag.
+func (f *FlagModel) DeprecationNotice() string {
+	return deprecationNotice(nil, f.Deprecated, f.DeprecationNote)
+}
+
 // IsBoolFlag determines if the current FlagModel is a switch.
 func (f *FlagModel) IsBoolFlag() bool {
 	if fl, ok := f.Value.(boolFlag); ok {
@@ -84,7 +98,7 @@ func (f *FlagModel) FormatPlaceHolder() string {
 	if f.PlaceHolder != "" {
 		return f.PlaceHolder
 	}
-	if len(f.Default) > 0 {
+	if len(f.Default) > 0 && !f.Secret {
 		ellipsis := ""
 		if len(f.Default) > 1 {
 			ellipsis = "..."
@@ -140,14 +154,22 @@ func (a *ArgModel) HelpWithEnvar() string {
 
 // ArgModel represents a read only value of an argument clause.
 type ArgModel struct {
-	Name        string
-	Help        string
-	Default     []string
-	Envar       string
-	PlaceHolder string
-	Required    bool
-	Hidden      bool
-	Value       Value
+	Name            string
+	Help            string
+	HelpLong        string
+	Default         []string
+	Envar           string
+	PlaceHolder     string
+	Required        bool
+	Hidden          bool
+	Deprecated      bool
+	DeprecationNote string // Message supplied to Deprecated()
+	Value           Value
+}
+
+// DeprecationNotice returns the notice displayed in the help of a deprecated argument.
+func (a *ArgModel) DeprecationNotice() string {
+	return deprecationNotice(nil, a.Deprecated, a.DeprecationNote)
 }
 
 func (a *ArgModel) String() string {
@@ -176,14 +198,19 @@ func (c *CmdGroupModel) FlattenedCommands() (out []*CmdModel) {
 
 // CmdModel represents a read only value of an command.
 type CmdModel struct {
-	Name        string
-	Aliases     []string
-	Help        string
-	HelpLong    string
-	FullCommand string
-	Depth       int
-	Hidden      bool
-	Default     bool
+	Name              string
+	Aliases           []string
+	Help              string
+	HelpLong          string
+	FullCommand       string
+	Depth             int
+	Hidden            bool
+	Default           bool
+	Deprecated        bool
+	DeprecationNote   string // Message supplied to Deprecated()
+	Category          string
+	EffectiveCategory string // Category of the command or of its nearest parent having one
+	Examples          []*ExampleModel
 	*FlagGroupModel
 	*ArgGroupModel
 	*CmdGroupModel
@@ -193,12 +220,33 @@ func (c *CmdModel) String() string {
 	return c.FullCommand
 }
 
+// DeprecationNotice returns the notice displayed in the help of a deprecated command.
+func (c *CmdModel) DeprecationNotice() string {
+	return deprecationNotice(nil, c.Deprecated, c.DeprecationNote)
+}
+
+// UserAliasModel represents a read only value of a user defined command alias.
+type UserAliasModel struct {
+	Name      string
+	Expansion []string
+}
+
+func (u *UserAliasModel) String() string {
+	return strings.Join(u.Expansion, " ")
+}
+
 // ApplicationModel represents a read only value of an application.
 type ApplicationModel struct {
-	Name    string
-	Help    string
-	Version string
-	Author  string
+	Name        string
+	Help        string
+	Version     string
+	Author      string
+	UserAliases []*UserAliasModel
+	Categories  []string // Declared order of the categories
+	Examples    []*ExampleModel
+	HelpTopics  []*HelpTopicModel
+	ManSection  string // Section of the man pages, 1 by default
+	ManDate     string
 	*ArgGroupModel
 	*CmdGroupModel
 	*FlagGroupModel
@@ -206,12 +254,28 @@ type ApplicationModel struct {
 
 // Model returns a read only value of an application.
 func (a *Application) Model() *ApplicationModel {
+	var aliases []*UserAliasModel
+	for _, name := range a.userAliasNames() {
+		aliases = append(aliases, &UserAliasModel{Name: name, Expansion: a.userAliases[name]})
+	}
+	manSection := a.manSection
+	if manSection == "" {
+		manSection = "1"
+	}
+	flags := a.flagGroup.Model()
+	a.translateBuiltinHelp(flags.Flags...)
 	return &ApplicationModel{
 		Name:           a.Name,
 		Help:           a.Help,
 		Version:        a.version,
 		Author:         a.author,
-		FlagGroupModel: a.flagGroup.Model(),
+		UserAliases:    aliases,
+		Categories:     a.categories,
+		Examples:       examplesModel(a.Name, a.examples),
+		HelpTopics:     a.helpTopicsModel(),
+		ManSection:     manSection,
+		ManDate:        a.manDate,
+		FlagGroupModel: flags,
 		ArgGroupModel:  a.argGroup.Model(),
 		CmdGroupModel:  a.cmdGroup.Model(),
 	}
@@ -228,14 +292,17 @@ func (a *argGroup) Model() *ArgGroupModel {
 // Model returns a read only value of an argument clause.
 func (a *ArgClause) Model() *ArgModel {
 	return &ArgModel{
-		Name:        a.name,
-		Help:        a.help,
-		Default:     a.defaultValues,
-		Envar:       a.envar,
-		PlaceHolder: a.placeholder,
-		Required:    a.required,
-		Hidden:      a.hidden,
-		Value:       a.value,
+		Name:            a.name,
+		Help:            a.help,
+		HelpLong:        a.helpLong,
+		Default:         a.defaultValues,
+		Envar:           a.envar,
+		PlaceHolder:     a.placeholder,
+		Required:        a.required,
+		Hidden:          a.hidden,
+		Deprecated:      a.deprecated,
+		DeprecationNote: a.deprecationMessage,
+		Value:           a.value,
 	}
 }
 
@@ -259,17 +326,30 @@ func (f *FlagClause) Model() *FlagModel {
 	}
 	sort.Strings(aliases)
 	sort.Strings(negatives)
+	defaults := f.defaultValues
+	if f.secret && len(defaults) > 0 {
+		defaults = make([]string, len(f.defaultValues))
+		for i := range defaults {
+			defaults[i] = secretMask
+		}
+	}
 	return &FlagModel{
 		Name:            f.name,
 		Help:            f.help,
+		HelpLong:        f.helpLong,
 		Short:           rune(f.shorthand),
-		Default:         f.defaultValues,
+		Default:         defaults,
 		Envar:           f.envar,
 		PlaceHolder:     f.placeholder,
 		Aliases:         aliases,
 		NegativeAliases: negatives,
 		Required:        f.required,
 		Hidden:          f.hidden,
+		Secret:          f.secret,
+		Deprecated:      f.deprecated,
+		DeprecationNote: f.deprecationMessage,
+		ReplacedBy:      f.replacedBy,
+		Category:        f.category,
 		Value:           f.value,
 	}
 }
@@ -289,16 +369,21 @@ func (c *CmdClause) Model() *CmdModel {
 		depth++
 	}
 	return &CmdModel{
-		Name:           c.name,
-		Aliases:        c.aliases,
-		Help:           c.help,
-		HelpLong:       c.helpLong,
-		Depth:          depth,
-		Hidden:         c.hidden,
-		Default:        c.isDefault,
-		FullCommand:    c.FullCommand(),
-		FlagGroupModel: c.flagGroup.Model(),
-		ArgGroupModel:  c.argGroup.Model(),
-		CmdGroupModel:  c.cmdGroup.Model(),
+		Name:              c.name,
+		Aliases:           c.aliases,
+		Help:              c.help,
+		HelpLong:          c.helpLong,
+		Depth:             depth,
+		Hidden:            c.hidden,
+		Default:           c.isDefault,
+		Deprecated:        c.deprecated,
+		DeprecationNote:   c.deprecationMessage,
+		Category:          c.category,
+		EffectiveCategory: c.effectiveCategory(),
+		Examples:          examplesModel(c.app.Name, c.examples),
+		FullCommand:       c.FullCommand(),
+		FlagGroupModel:    c.flagGroup.Model(),
+		ArgGroupModel:     c.argGroup.Model(),
+		CmdGroupModel:     c.cmdGroup.Model(),
 	}
 }
@@ -0,0 +1,69 @@
+package kingpin
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// UsePager displays the help (usage, long help and man page) through the
+// pager defined by the PAGER environment variable (less -R by default) when
+// the usage writer is a terminal.
+func (a *Application) UsePager() *Application {
+	a.usePager = true
+	return a
+}
+
+// Returns the pager command line. Overridden by the tests.
+var pagerCommand = func() []string {
+	if pager := strings.Fields(os.Getenv("PAGER")); len(pager) > 0 {
+		return pager
+	}
+	return []string{"less", "-R"}
+}
+
+// Runs fn with the usage writer piped through the pager if it is enabled. The
+// output is written directly if the pager cannot be started.
+func (a *Application) withPager(fn func() error) error {
+	if !a.usePager || a.pagedWriter != nil || !isUsageTerminal(a.usageWriter) {
+		return fn()
+	}
+	args := pagerCommand()
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout, cmd.Stderr = a.usageWriter, a.errorWriter
+	in, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		return fn()
+	}
+
+	a.pagedWriter, a.usageWriter = a.usageWriter, in
+	defer func() {
+		a.usageWriter, a.pagedWriter = a.pagedWriter, nil
+	}()
+	err = fn()
+	in.Close()
+	waitErr := cmd.Wait()
+	if errors.Is(err, syscall.EPIPE) {
+		// The pager has been closed before the end of the output
+		err = nil
+	}
+	if err == nil {
+		err = waitErr
+	}
+	return err
+}
+
+// Returns the writer on which the usage is finally displayed, which can be a
+// terminal even if the usage is piped through the pager.
+func (a *Application) displayWriter() io.Writer {
+	if a.pagedWriter != nil {
+		return a.pagedWriter
+	}
+	return a.usageWriter
+}
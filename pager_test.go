@@ -0,0 +1,68 @@
+package kingpin
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withPagerCommand(t *testing.T, command ...string) {
+	previous := pagerCommand
+	pagerCommand = func() []string { return command }
+	t.Cleanup(func() { pagerCommand = previous })
+}
+
+func TestUsePager(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test pagers are not available on windows")
+	}
+	withUsageTerminal(t, true)
+	withPagerCommand(t, "cat")
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).UsePager()
+	app.Terminate(func(status int) { panic(status) })
+	app.Command("status", "Show the status.")
+
+	assert.Equal(t, 0, parseHelp(app, "--help"))
+	assert.Contains(t, buf.String(), "usage: test")
+	assert.Contains(t, buf.String(), "Show the status.")
+	assert.Equal(t, &buf, app.usageWriter, "the writer is restored")
+
+	buf.Reset()
+	withPagerCommand(t, "tr", "a-z", "A-Z")
+	assert.NoError(t, app.withPager(func() error {
+		return app.UsageForContext(&ParseContext{flags: newFlagGroup(), arguments: newArgGroup()})
+	}))
+	assert.Contains(t, buf.String(), "USAGE: TEST", "the output is piped through the pager")
+}
+
+func TestPagerFallback(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf).UsePager()
+
+	withUsageTerminal(t, false)
+	withPagerCommand(t, "tr", "a-z", "A-Z")
+	assert.NoError(t, app.withPager(func() error {
+		_, err := app.usageWriter.Write([]byte("not a terminal\n"))
+		return err
+	}))
+
+	withUsageTerminal(t, true)
+	withPagerCommand(t, "/nonexistent/pager")
+	assert.NoError(t, app.withPager(func() error {
+		_, err := app.usageWriter.Write([]byte("no pager\n"))
+		return err
+	}))
+	assert.Equal(t, "not a terminal\nno pager\n", buf.String())
+}
+
+func TestPagerCommand(t *testing.T) {
+	defer os.Setenv("PAGER", os.Getenv("PAGER"))
+	os.Setenv("PAGER", "more -s")
+	assert.Equal(t, []string{"more", "-s"}, pagerCommand())
+	os.Setenv("PAGER", "")
+	assert.Equal(t, []string{"less", "-R"}, pagerCommand())
+}
@@ -2,7 +2,9 @@ package kingpin
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"unicode/utf8"
@@ -93,17 +95,27 @@ type ParseElement struct {
 // *ArgClause and *CmdClause values and their corresponding arguments (if
 // any).
 type ParseContext struct {
-	SelectedCommand  *CmdClause
-	ignoreDefault    bool
-	argsOnly         bool
-	peek             []*Token
-	argi             int // Index of current command-line arg we're processing.
-	args             []string
-	rawArgs          []string
-	flags            *flagGroup
-	arguments        *argGroup
-	argumenti        int          // Cursor into arguments
-	appUnmanagedArgs *Application // Only set if AllowUnmanaged is set
+	SelectedCommand *CmdClause
+	ignoreDefault   bool
+	argsOnly        bool
+	literalNext     bool // Disables file expansion for the next argument
+	dashNext        bool // Keeps - as the value of the next argument
+	expansion       *fileExpansion
+	peek            []*Token
+	argi            int // Index of current command-line arg we're processing.
+	args            []string
+	rawArgs         []string
+	flags           *flagGroup
+	arguments       *argGroup
+	argumenti       int // Cursor into arguments
+	allowUnmanaged  bool
+	unmanaged       []string
+	readOnly        bool // Set if the parse must not modify the application (see Application.Freeze)
+	completionAlts  map[*CmdClause][]string
+	messages        MessageCatalog  // Translates the errors, see Application.Messages
+	stdin           io.Reader       // Source of the flag values given as -
+	ctx             context.Context // Set by Application.ParseWithContext
+	opened          []io.Closer     // Values holding resources set by the parse, see Application.Close
 	// Flags, arguments and commands encountered and collected during parse.
 	Elements []*ParseElement
 }
@@ -124,19 +136,39 @@ func (p *ParseContext) next() {
 	p.args = p.args[1:]
 }
 
+// Context returns the context supplied to Application.ParseWithContext, or
+// context.Background() if the command line has been parsed without context.
+func (p *ParseContext) Context() context.Context {
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// UnmanagedArgs returns the arguments that were not handled by the parser
+// (only collected if AllowUnmanaged is set on the application).
+func (p *ParseContext) UnmanagedArgs() []string {
+	return p.unmanaged
+}
+
 // HasTrailingArgs returns true if there are unparsed command-line arguments.
 // This can occur if the parser can not match remaining arguments.
 func (p *ParseContext) HasTrailingArgs() bool {
 	return len(p.args) > 0
 }
 
-func tokenize(args []string, ignoreDefault bool) *ParseContext {
+func tokenize(args []string, ignoreDefault bool, expansion *fileExpansion) *ParseContext {
+	if expansion == nil {
+		expansion = fileExpansion{}.resolve()
+	}
 	return &ParseContext{
-		ignoreDefault: ignoreDefault,
-		args:          args,
-		rawArgs:       args,
-		flags:         newFlagGroup(),
-		arguments:     newArgGroup(),
+		ignoreDefault:  ignoreDefault,
+		expansion:      expansion,
+		completionAlts: map[*CmdClause][]string{},
+		args:           args,
+		rawArgs:        args,
+		flags:          newFlagGroup(),
+		arguments:      newArgGroup(),
 	}
 }
 
@@ -203,7 +235,11 @@ func (p *ParseContext) Next() *Token {
 
 	if strings.HasPrefix(arg, "-") {
 		if len(arg) == 1 {
-			return &Token{Index: p.argi, Type: TokenArg}
+			token := &Token{Index: p.argi, Type: TokenArg}
+			if p.dashNext {
+				token.Value = arg
+			}
+			return token
 		}
 		shortRune, size := utf8.DecodeRuneInString(arg[1:])
 		short := string(shortRune)
@@ -225,8 +261,8 @@ func (p *ParseContext) Next() *Token {
 			p.args = append([]string{"-" + arg[size+1:]}, p.args...)
 		}
 		return &Token{p.argi, TokenShort, short}
-	} else if EnableFileExpansion && strings.HasPrefix(arg, "@") {
-		expanded, err := ExpandArgsFromFile(arg[1:])
+	} else if filename, ok := p.expansion.fileName(arg); ok && !p.literalNext {
+		expanded, err := p.expansion.expand(filename)
 		if err != nil {
 			return &Token{p.argi, TokenError, err.Error()}
 		}
@@ -301,6 +337,15 @@ func ExpandArgsFromFile(filename string) (out []string, err error) {
 		return nil, fmt.Errorf("failed to open arguments file %q: %s", filename, err)
 	}
 	defer r.Close()
+	out, err = splitArgsLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read arguments from %q: %s", filename, err)
+	}
+	return
+}
+
+// Returns the non-empty lines of r that are not comments.
+func splitArgsLines(r io.Reader) (out []string, err error) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -309,11 +354,7 @@ func ExpandArgsFromFile(filename string) (out []string, err error) {
 		}
 		out = append(out, line)
 	}
-	err = scanner.Err()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read arguments from %q: %s", filename, err)
-	}
-	return
+	return out, scanner.Err()
 }
 
 func parse(context *ParseContext, app *Application) (err error) {
@@ -323,6 +364,7 @@ func parse(context *ParseContext, app *Application) (err error) {
 
 	cmds := app.cmdGroup
 	ignoreDefault := context.ignoreDefault
+	helpArgs := false // Set when the arguments of the help command can designate flags
 
 loop:
 	for !context.EOL() && !context.Error() {
@@ -330,10 +372,17 @@ loop:
 
 		switch token.Type {
 		case TokenLong, TokenShort:
+			if helpArgs && !context.flags.has(token) {
+				if arg := context.nextArg(); arg != nil {
+					context.matchedArg(arg, token.String())
+					context.Next()
+					break
+				}
+			}
 			if flag, err := context.flags.parse(context); err != nil {
 				if _, parseError := err.(aliasError); !parseError && !ignoreDefault {
 					if cmd := cmds.defaultSubcommand(); cmd != nil {
-						cmd.completionAlts = cmds.cmdNames()
+						context.completionAlts[cmd] = cmds.cmdNames()
 						if err := context.matchedCmd(cmd); err != nil {
 							return err
 						}
@@ -353,18 +402,19 @@ loop:
 				if !ok {
 					if !ignoreDefault {
 						if cmd = cmds.defaultSubcommand(); cmd != nil {
-							cmd.completionAlts = cmds.cmdNames()
+							context.completionAlts[cmd] = cmds.cmdNames()
 							selectedDefault = true
 						}
 					}
 					if cmd == nil {
-						return fmt.Errorf("expected command but got %q", token)
+						return context.errorf("expected command but got %q", token)
 					}
 				}
 				if cmd == HelpCommand {
 					ignoreDefault = true
 				}
-				cmd.completionAlts = nil
+				helpArgs = cmd == app.HelpCommand
+				delete(context.completionAlts, cmd)
 				if err := context.matchedCmd(cmd); err != nil {
 					return err
 				}
@@ -385,8 +435,8 @@ loop:
 				context.matchedArg(arg, token.String())
 				context.Next()
 			} else {
-				if context.appUnmanagedArgs != nil {
-					context.appUnmanagedArgs.Unmanaged = append(context.appUnmanagedArgs.Unmanaged, context.current())
+				if context.allowUnmanaged {
+					context.unmanaged = append(context.unmanaged, context.current())
 					context.Next()
 					continue
 				}
@@ -402,7 +452,7 @@ loop:
 	// Move to innermost default command.
 	for !ignoreDefault {
 		if cmd := cmds.defaultSubcommand(); cmd != nil {
-			cmd.completionAlts = cmds.cmdNames()
+			context.completionAlts[cmd] = cmds.cmdNames()
 			if err := context.matchedCmd(cmd); err != nil {
 				return err
 			}
@@ -417,7 +467,11 @@ loop:
 	}
 
 	if !context.EOL() {
-		return fmt.Errorf("unexpected %s", context.Peek())
+		return context.errorf("unexpected %s", context.Peek())
+	}
+
+	if context.readOnly {
+		return
 	}
 
 	// Set defaults for all remaining args.
@@ -107,7 +107,7 @@ func TestParserExpandFromFileMultipleFlags(t *testing.T) {
 func TestParseContextPush(t *testing.T) {
 	app := New("test", "")
 	app.Command("foo", "").Command("bar", "")
-	c := tokenize([]string{"foo", "bar"}, false)
+	c := tokenize([]string{"foo", "bar"}, false, nil)
 	a := c.Next()
 	assert.Equal(t, TokenArg, a.Type)
 	b := c.Next()
@@ -17,6 +17,7 @@ type Settings interface {
 type parserMixin struct {
 	value    Value
 	required bool
+	restore  func() // Restores the initial value of the target, see Application.Reset
 }
 
 func (p *parserMixin) SetText(text Text) {
@@ -25,6 +26,21 @@ func (p *parserMixin) SetText(text Text) {
 
 func (p *parserMixin) SetValue(value Value) {
 	p.value = value
+	p.restore = nil
+	if bound, ok := value.(boundValue); ok {
+		p.restore = snapshotTarget(bound.target())
+	}
+}
+
+// Resets the value (see Resettable) and restores the initial value of its
+// target.
+func (p *parserMixin) reset() {
+	if value, ok := p.value.(Resettable); ok {
+		value.Reset()
+	}
+	if p.restore != nil {
+		p.restore()
+	}
 }
 
 // StringMap provides key=value parsing into a map.
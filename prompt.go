@@ -0,0 +1,198 @@
+package kingpin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Prompt describes a missing required value that the user is asked for.
+type Prompt struct {
+	// Name is the flag (--name) or argument (<name>) being prompted.
+	Name        string
+	Help        string
+	PlaceHolder string
+	// Options contains the valid choices if the value is an enum.
+	Options []string
+	// Secret indicates that the input should not be echoed.
+	Secret bool
+	// Error is the error returned by the previous answer, if any.
+	Error error
+}
+
+// Prompter is the interface used to interactively ask for missing required
+// values. It returns the raw value entered by the user.
+type Prompter interface {
+	Prompt(prompt *Prompt) (string, error)
+}
+
+// PromptForMissing instructs the application to prompt for missing required
+// flags and arguments instead of returning an error. Unless a custom Prompter
+// is configured, prompting only occurs if stdin is a terminal.
+func (a *Application) PromptForMissing() *Application {
+	a.promptForMissing = true
+	return a
+}
+
+// Prompter sets the Prompter used to ask for missing required values. It
+// implies PromptForMissing().
+func (a *Application) Prompter(prompter Prompter) *Application {
+	a.promptForMissing = true
+	a.prompter = prompter
+	return a
+}
+
+// Returns the prompter of a parse, nil if the user must not be prompted. The
+// terminal prompter is created for each parse, so that the parses of a frozen
+// application do not share it.
+func (a *Application) activePrompter() Prompter {
+	if !a.promptForMissing {
+		return nil
+	}
+	if a.prompter != nil {
+		return a.prompter
+	}
+	if !isTerminal(os.Stdin) {
+		return nil
+	}
+	return newTerminalPrompter(os.Stdin, a.errorWriter, a.messages)
+}
+
+// Prompt for all required flags and arguments that were not supplied and add
+// the values to the parse context.
+func (a *Application) promptMissing(context *ParseContext, prompter Prompter) error {
+	provided := map[interface{}]bool{}
+	for _, element := range context.Elements {
+		provided[element.Clause] = true
+		if flag, ok := element.Clause.(*FlagClause); ok {
+			provided[flag.target()] = true
+			if replacement := flag.replacement(context); replacement != nil {
+				provided[replacement] = true
+			}
+		}
+	}
+
+	for _, flag := range context.flags.flagOrder {
+		if provided[flag] || !flag.needsValue() {
+			continue
+		}
+		prompt := &Prompt{
+			Name:        "--" + flag.name,
+			Help:        flag.help,
+			PlaceHolder: flag.placeholder,
+			Options:     valueOptions(flag.value),
+			Secret:      flag.secret,
+		}
+		value, err := askValue(prompter, prompt, flag.setValue, context.messages)
+		if err != nil {
+			return err
+		}
+		context.trackCloser(flag.value)
+		if flag.secret {
+			value = ""
+		}
+		context.matchedFlag(flag, value)
+	}
+
+	for _, arg := range context.arguments.args {
+		if provided[arg] || !arg.needsValue() {
+			continue
+		}
+		prompt := &Prompt{
+			Name:        "<" + arg.name + ">",
+			Help:        arg.help,
+			PlaceHolder: arg.placeholder,
+			Options:     valueOptions(arg.value),
+		}
+		value, err := askValue(prompter, prompt, arg.value.Set, context.messages)
+		if err != nil {
+			return err
+		}
+		context.trackCloser(arg.value)
+		context.matchedArg(arg, value)
+	}
+	return nil
+}
+
+// Prompt until a value is accepted by the setter.
+func askValue(prompter Prompter, prompt *Prompt, set func(string) error, catalog MessageCatalog) (string, error) {
+	for {
+		value, err := prompter.Prompt(prompt)
+		if err != nil {
+			return "", fmt.Errorf("unable to read value for %s: %s", prompt.Name, err)
+		}
+		if prompt.Error = translateError(catalog, set(value)); prompt.Error == nil {
+			return value, nil
+		}
+	}
+}
+
+func valueOptions(value Value) []string {
+	if e, ok := value.(Enumerable); ok {
+		return e.Options()
+	}
+	return nil
+}
+
+type terminalPrompter struct {
+	in       *bufio.Reader
+	source   io.Reader
+	out      io.Writer
+	messages MessageCatalog
+}
+
+func newTerminalPrompter(in io.Reader, out io.Writer, catalog MessageCatalog) *terminalPrompter {
+	return &terminalPrompter{in: bufio.NewReader(in), source: in, out: out, messages: catalog}
+}
+
+func (t *terminalPrompter) Prompt(prompt *Prompt) (value string, err error) {
+	if prompt.Error != nil {
+		fmt.Fprintf(t.out, "%s: %s\n", translate(t.messages, "error"), prompt.Error)
+	}
+	label := prompt.Help
+	if label == "" {
+		label = prompt.Name
+	} else {
+		label = fmt.Sprintf("%s (%s)", label, prompt.Name)
+	}
+	if len(prompt.Options) > 0 {
+		fmt.Fprintf(t.out, "%s:\n", label)
+		for i, option := range prompt.Options {
+			fmt.Fprintf(t.out, "  %d) %s\n", i+1, option)
+		}
+		fmt.Fprintf(t.out, "%s: ", translate(t.messages, "Choice"))
+	} else if prompt.PlaceHolder != "" {
+		fmt.Fprintf(t.out, "%s [%s]: ", label, prompt.PlaceHolder)
+	} else {
+		fmt.Fprintf(t.out, "%s: ", label)
+	}
+
+	read := func() error {
+		value, err = t.in.ReadString('\n')
+		if err == io.EOF && value != "" {
+			err = nil
+		}
+		return err
+	}
+	if prompt.Secret {
+		err = withoutEcho(t.source, read)
+		fmt.Fprintln(t.out)
+	} else {
+		err = read()
+	}
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimRight(value, "\r\n")
+
+	if len(prompt.Options) > 0 {
+		// The user can either enter the choice number or the value itself
+		if i, err := strconv.Atoi(value); err == nil && i > 0 && i <= len(prompt.Options) {
+			value = prompt.Options[i-1]
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,118 @@
+package kingpin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePrompter struct {
+	answers []string
+	prompts []Prompt
+}
+
+func (f *fakePrompter) Prompt(prompt *Prompt) (string, error) {
+	f.prompts = append(f.prompts, *prompt)
+	if len(f.answers) == 0 {
+		return "", io.EOF
+	}
+	answer := f.answers[0]
+	f.answers = f.answers[1:]
+	return answer, nil
+}
+
+func TestPromptForMissing(t *testing.T) {
+	app := newTestApp()
+	name := app.Flag("name", "Your name.").Required().String()
+	token := app.Flag("token", "API token.").Required().Secret().String()
+	count := app.Flag("count", "Count.").Required().Int()
+	format := app.Flag("format", "Output format.").Required().Enum("json", "yaml")
+	optional := app.Flag("optional", "").String()
+	file := app.Arg("file", "File to process.").Required().String()
+
+	prompter := &fakePrompter{answers: []string{"token", "x", "3", "xml", "yaml", "file.txt"}}
+	app.Prompter(prompter)
+	_, err := app.Parse([]string{"--name=joe"})
+	assert.NoError(t, err)
+	assert.Equal(t, "joe", *name)
+	assert.Equal(t, "token", *token)
+	assert.Equal(t, 3, *count)
+	assert.Equal(t, "yaml", *format)
+	assert.Equal(t, "", *optional)
+	assert.Equal(t, "file.txt", *file)
+
+	var names []string
+	for _, prompt := range prompter.prompts {
+		names = append(names, prompt.Name)
+	}
+	assert.Equal(t, []string{"--token", "--count", "--count", "--format", "--format", "<file>"}, names)
+	assert.True(t, prompter.prompts[0].Secret)
+	assert.Equal(t, "API token.", prompter.prompts[0].Help)
+	assert.Nil(t, prompter.prompts[1].Error)
+	assert.Error(t, prompter.prompts[2].Error)
+	assert.Equal(t, []string{"json", "yaml"}, prompter.prompts[3].Options)
+	assert.EqualError(t, prompter.prompts[4].Error, "enum value must be one of json,yaml, got 'xml'")
+}
+
+func TestPromptForMissingActions(t *testing.T) {
+	app := newTestApp()
+	var called bool
+	app.Flag("name", "").Required().Action(func(*ParseContext) error {
+		called = true
+		return nil
+	}).String()
+	app.Prompter(&fakePrompter{answers: []string{"joe"}})
+	_, err := app.Parse(nil)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestPromptForMissingError(t *testing.T) {
+	app := newTestApp()
+	app.Flag("name", "").Required().String()
+	app.Prompter(&fakePrompter{})
+	_, err := app.Parse(nil)
+	assert.EqualError(t, err, "unable to read value for --name: EOF")
+}
+
+func TestPromptForMissingNotTerminal(t *testing.T) {
+	if isTerminal(os.Stdin) {
+		t.Skip("stdin is a terminal")
+	}
+	app := newTestApp()
+	app.Flag("name", "").Required().String()
+	app.PromptForMissing()
+	_, err := app.Parse(nil)
+	assert.EqualError(t, err, "required flag(s) '--name' not provided")
+}
+
+func TestTerminalPrompter(t *testing.T) {
+	var out bytes.Buffer
+	prompter := newTerminalPrompter(strings.NewReader("joe\n2\nsecret"), &out, nil)
+
+	value, err := prompter.Prompt(&Prompt{Name: "--name", Help: "Your name.", PlaceHolder: "NAME"})
+	assert.NoError(t, err)
+	assert.Equal(t, "joe", value)
+
+	value, err = prompter.Prompt(&Prompt{Name: "--format", Options: []string{"json", "yaml"}, Error: fmt.Errorf("bad value")})
+	assert.NoError(t, err)
+	assert.Equal(t, "yaml", value)
+
+	value, err = prompter.Prompt(&Prompt{Name: "--token", Secret: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", value)
+
+	_, err = prompter.Prompt(&Prompt{Name: "--other"})
+	assert.Equal(t, io.EOF, err)
+
+	expected := "Your name. (--name) [NAME]: " +
+		"error: bad value\n--format:\n  1) json\n  2) yaml\nChoice: " +
+		"--token: \n" +
+		"--other: "
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,512 @@
+package kingpin
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ValueSource indicates where the value of a flag or an argument comes from.
+type ValueSource int
+
+const (
+	// SourceUnset indicates that no value has been supplied.
+	SourceUnset ValueSource = iota
+	// SourceDefault indicates that the default value has been used.
+	SourceDefault
+	// SourceEnvar indicates that the value comes from an environment variable.
+	SourceEnvar
+	// SourceCommandLine indicates that the value has been supplied on the command line.
+	SourceCommandLine
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceEnvar:
+		return "envar"
+	case SourceCommandLine:
+		return "command line"
+	}
+	return "unset"
+}
+
+// Provenance describes how the value of a flag or an argument has been obtained.
+type Provenance struct {
+	Source ValueSource
+	Envar  string   // Name of the environment variable if Source is SourceEnvar
+	Values []string // Raw values (redacted for secret flags)
+}
+
+// Result holds the values of a parsed command line. The values are stored
+// independently of the targets bound to the flags and arguments, which are
+// only modified by Apply().
+//
+// Values are identified by the flag or argument name, prefixed by the
+// command path for the ones defined on commands (e.g. "server.start.port").
+// A name without prefix is also resolved from the innermost selected command
+// up to the application.
+type Result struct {
+	app       *Application
+	commands  []*CmdClause
+	keys      []string
+	values    map[string]*resultValue
+	unmanaged []string
+	trailing  []string
+}
+
+type resultValue struct {
+	flag       *FlagClause
+	arg        *ArgClause
+	value      Value
+	raw        []string // Values supplied to value, kept to apply them to the target
+	provenance Provenance
+}
+
+// ParseResult parses the command line and returns the resulting values
+// without modifying the targets bound to the flags and arguments. The
+// application must be frozen (see Freeze) before ParseResult is called
+// concurrently.
+//
+// Unlike Parse, the actions and validators are not executed and the user is
+// not prompted for missing values. The files opened by the values of the
+// result must be closed with Result.Close().
+func (a *Application) ParseResult(args []string) (*Result, error) {
+	context, err := a.parseContextMode(false, true, args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := newResult(a, context)
+	if err != nil {
+		return nil, translateError(a.messages, err)
+	}
+	return result, nil
+}
+
+func newResult(a *Application, context *ParseContext) (*Result, error) {
+	r := &Result{
+		app:       a,
+		values:    map[string]*resultValue{},
+		unmanaged: context.unmanaged,
+	}
+	for i, arg := range context.rawArgs {
+		if arg == "--" {
+			r.trailing = context.rawArgs[i+1:]
+			break
+		}
+	}
+
+	keys := map[interface{}]string{}
+	collectResultKeys(keys, a.flagGroup, a.argGroup, a.cmdGroup, "")
+	flags := map[*FlagClause]*resultValue{}
+	args := map[*ArgClause]*resultValue{}
+	add := func(key string, v *resultValue) {
+		r.keys = append(r.keys, key)
+		r.values[key] = v
+	}
+	for _, flag := range context.flags.flagOrder {
+		if flag.secretOf == nil {
+			flags[flag] = &resultValue{flag: flag, value: cloneValue(flag.value)}
+			add(keys[flag], flags[flag])
+		}
+	}
+	for _, arg := range context.arguments.args {
+		args[arg] = &resultValue{arg: arg, value: cloneValue(arg.value)}
+		add(keys[arg], args[arg])
+	}
+
+	seen := map[*FlagClause]bool{}
+	for _, element := range context.Elements {
+		switch clause := element.Clause.(type) {
+		case *FlagClause:
+			v := flags[clause.target()]
+			if seen[clause.target()] && !isCumulative(v.value) {
+				return nil, context.errorf("flag '%s' cannot be repeated", clause.name)
+			}
+			seen[clause.target()] = true
+			var value string
+			var err error
+			if clause.secretOf != nil {
+				value, err = readSecretFile(clause.secretOf, *element.Value, context.stdin)
+			} else {
+				value, err = clause.resolveFileValue(clause.valueWithoutTopic(*element.Value), context.stdin)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = v.set(SourceCommandLine, value); err != nil {
+				return nil, err
+			}
+			if replacement := clause.replacement(context); replacement != nil {
+				if err = flags[replacement].set(SourceCommandLine, value); err != nil {
+					return nil, err
+				}
+			}
+		case *ArgClause:
+			if err := args[clause].set(SourceCommandLine, *element.Value); err != nil {
+				return nil, err
+			}
+		case *CmdClause:
+			r.commands = append(r.commands, clause)
+		}
+	}
+
+	if n := len(r.commands); n > 0 && len(r.commands[n-1].commands) > 0 {
+		return nil, context.errorf("must select a subcommand of '%s'", r.commands[n-1].FullCommand())
+	} else if n == 0 && a.cmdGroup.have() {
+		return nil, ErrCommandNotSpecified
+	}
+
+	var missingFlags []string
+	for _, flag := range context.flags.flagOrder {
+		v := flags[flag]
+		if v == nil {
+			continue
+		}
+		if v.provenance.Source == SourceCommandLine {
+			if isCumulative(v.value) && flag.HasEnvarValue() {
+				// Like Parse, the environment variables are joined to the provided values
+				if err := v.setAll(SourceCommandLine, flag.GetSplitEnvarValue()); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := v.setDefault(&flag.envarMixin, flag.defaultValues, isCumulative(v.value)); err != nil {
+			return nil, err
+		}
+		if flag.needsValue() {
+			missingFlags = append(missingFlags, fmt.Sprintf("'--%s'", flag.name))
+		}
+	}
+	if len(missingFlags) != 0 {
+		return nil, context.errorf("required flag(s) %s not provided", strings.Join(missingFlags, ", "))
+	}
+
+	for _, arg := range context.arguments.args {
+		v := args[arg]
+		if v.provenance.Source == SourceCommandLine {
+			continue
+		}
+		if err := v.setDefault(&arg.envarMixin, arg.defaultValues, isCumulative(v.value)); err != nil {
+			return nil, err
+		}
+		if arg.needsValue() {
+			return nil, context.errorf("required argument '%s' not provided", arg.name)
+		}
+	}
+	return r, nil
+}
+
+// Associates the flags and the arguments to their result key.
+func collectResultKeys(keys map[interface{}]string, flags *flagGroup, args *argGroup, cmds *cmdGroup, prefix string) {
+	for _, flag := range flags.flagOrder {
+		keys[flag] = prefix + flag.name
+	}
+	for _, arg := range args.args {
+		keys[arg] = prefix + arg.name
+	}
+	for _, cmd := range cmds.commandOrder {
+		collectResultKeys(keys, cmd.flagGroup, cmd.argGroup, cmd.cmdGroup, prefix+cmd.name+".")
+	}
+}
+
+func readSecretFile(flag *FlagClause, path string, stdin io.Reader) (string, error) {
+	content, err := readValueFile(path, DefaultFileValueSizeLimit, stdin)
+	if err != nil {
+		return "", fmt.Errorf("unable to read value of '--%s': %s", flag.name, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func (v *resultValue) set(source ValueSource, value string) error {
+	if err := v.value.Set(value); err != nil {
+		if v.flag != nil && v.flag.secret {
+			return redactError(err, v.flag.name, value)
+		}
+		return err
+	}
+	v.raw = append(v.raw, value)
+	if v.flag != nil && v.flag.secret {
+		value = secretMask
+	}
+	v.provenance.Source = source
+	v.provenance.Values = append(v.provenance.Values, value)
+	return nil
+}
+
+func (v *resultValue) setAll(source ValueSource, values []string) error {
+	for _, value := range values {
+		if err := v.set(source, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *resultValue) setDefault(envar *envarMixin, defaults []string, cumulative bool) error {
+	if envar.HasEnvarValue() {
+		v.provenance.Envar = envar.envar
+		if !cumulative {
+			return v.set(SourceEnvar, envar.GetEnvarValue())
+		}
+		return v.setAll(SourceEnvar, envar.GetSplitEnvarValue())
+	}
+	return v.setAll(SourceDefault, defaults)
+}
+
+// Implemented by the values which can take over the state of their clone
+// instead of being set again from the raw values, such as the file values
+// which would open the file a second time.
+type adopter interface {
+	adopt(clone Value) error
+}
+
+// Returns a copy of the value that is not bound to the original target.
+func cloneValue(value Value) Value {
+	if c, ok := value.(cloneable); ok {
+		if clone := c.clone(); clone != nil {
+			return clone
+		}
+	}
+	return &rawValue{cumulative: isCumulative(value)}
+}
+
+func isCumulative(value Value) bool {
+	if r, ok := value.(repeatableFlag); ok {
+		return r.IsCumulative()
+	}
+	return false
+}
+
+// -- raw Value, used for values that cannot be cloned
+
+type rawValue struct {
+	values     []string
+	cumulative bool
+}
+
+func (r *rawValue) Set(value string) error {
+	if !r.cumulative {
+		r.values = nil
+	}
+	r.values = append(r.values, value)
+	return nil
+}
+
+func (r *rawValue) Get() interface{} {
+	if r.cumulative {
+		return r.values
+	}
+	return r.String()
+}
+
+func (r *rawValue) String() string     { return strings.Join(r.values, ",") }
+func (r *rawValue) IsCumulative() bool { return r.cumulative }
+
+func (r *Result) lookup(name string) *resultValue {
+	if v := r.values[name]; v != nil {
+		return v
+	}
+	for i := len(r.commands); i > 0; i-- {
+		var path []string
+		for _, cmd := range r.commands[:i] {
+			path = append(path, cmd.name)
+		}
+		if v := r.values[strings.Join(path, ".")+"."+name]; v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// Command returns the selected command as a space separated list of
+// sub commands (as returned by Parse).
+func (r *Result) Command() string {
+	return strings.Join(r.CommandPath(), " ")
+}
+
+// CommandPath returns the names of the selected command and its parents.
+func (r *Result) CommandPath() []string {
+	path := make([]string, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		path = append(path, cmd.name)
+	}
+	return path
+}
+
+// Keys returns the keys of all flags and arguments available for the selected command.
+func (r *Result) Keys() []string {
+	return r.keys
+}
+
+// Unmanaged returns the arguments not handled by the parser (see
+// Application.AllowUnmanaged).
+func (r *Result) Unmanaged() []string {
+	return r.unmanaged
+}
+
+// Trailing returns the arguments following the -- separator.
+func (r *Result) Trailing() []string {
+	return r.trailing
+}
+
+// Provenance returns the source of the named value.
+func (r *Result) Provenance(name string) Provenance {
+	if v := r.lookup(name); v != nil {
+		return v.provenance
+	}
+	return Provenance{}
+}
+
+// IsSet returns true if the named value has been supplied on the command line.
+func (r *Result) IsSet(name string) bool {
+	return r.Provenance(name).Source == SourceCommandLine
+}
+
+// Get returns the named value or nil if there is no such flag or argument.
+// Values that do not implement Getter are returned as strings.
+func (r *Result) Get(name string) interface{} {
+	v := r.lookup(name)
+	if v == nil {
+		return nil
+	}
+	if getter, ok := v.value.(Getter); ok {
+		return getter.Get()
+	}
+	return v.value.String()
+}
+
+// String returns the named value as a string.
+func (r *Result) String(name string) string {
+	if s, ok := r.Get(name).(string); ok {
+		return s
+	}
+	if v := r.lookup(name); v != nil {
+		return v.value.String()
+	}
+	return ""
+}
+
+// Strings returns the named value as a slice of strings.
+func (r *Result) Strings(name string) []string {
+	switch s := r.Get(name).(type) {
+	case []string:
+		return s
+	case *[]string:
+		return *s
+	}
+	return nil
+}
+
+// StringMap returns the named value as a map of strings.
+func (r *Result) StringMap(name string) map[string]string {
+	m, _ := r.Get(name).(map[string]string)
+	return m
+}
+
+// Bool returns the named value as a bool.
+func (r *Result) Bool(name string) bool {
+	b, _ := r.Get(name).(bool)
+	return b
+}
+
+// Int returns the named value as an int.
+func (r *Result) Int(name string) int {
+	i, _ := r.Get(name).(int)
+	return i
+}
+
+// Int64 returns the named value as an int64.
+func (r *Result) Int64(name string) int64 {
+	i, _ := r.Get(name).(int64)
+	return i
+}
+
+// Uint64 returns the named value as an uint64.
+func (r *Result) Uint64(name string) uint64 {
+	i, _ := r.Get(name).(uint64)
+	return i
+}
+
+// Float64 returns the named value as a float64.
+func (r *Result) Float64(name string) float64 {
+	f, _ := r.Get(name).(float64)
+	return f
+}
+
+// Duration returns the named value as a time.Duration.
+func (r *Result) Duration(name string) time.Duration {
+	d, _ := r.Get(name).(time.Duration)
+	return d
+}
+
+// IP returns the named value as a net.IP.
+func (r *Result) IP(name string) net.IP {
+	ip, _ := r.Get(name).(net.IP)
+	return ip
+}
+
+// Apply sets the values of the result into the targets bound to the flags and
+// arguments, as Parse would. It must not be called concurrently. The files
+// opened by the result are then owned by the application (see
+// Application.Close).
+func (r *Result) Apply() error {
+	for _, key := range r.keys {
+		v := r.values[key]
+		var target Value
+		if v.flag != nil {
+			target = v.flag.value
+		} else {
+			target = v.arg.value
+		}
+		var err error
+		if a, ok := target.(adopter); ok && len(v.raw) > 0 {
+			err = a.adopt(v.value)
+		} else {
+			err = v.apply()
+		}
+		if err != nil {
+			return translateError(r.app.messages, err)
+		}
+		if closer, ok := target.(io.Closer); ok && len(v.raw) > 0 {
+			r.app.addOpened(closer)
+		}
+		if v.flag != nil && v.provenance.Source == SourceCommandLine {
+			v.flag.isSetByUser()
+		}
+	}
+	r.app.Unmanaged = append(r.app.Unmanaged, r.unmanaged...)
+	return nil
+}
+
+// Close closes the files opened by the values of the result which have not
+// been applied (see File()). The first error encountered is returned.
+func (r *Result) Close() (err error) {
+	for _, key := range r.keys {
+		if closer, ok := r.values[key].value.(io.Closer); ok {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	return
+}
+
+func (v *resultValue) apply() error {
+	for _, value := range v.raw {
+		var err error
+		if v.flag != nil {
+			err = v.flag.setValue(value)
+		} else {
+			err = v.arg.value.Set(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package kingpin
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newResultTestApp() (*Application, *string, *time.Duration) {
+	app := newTestApp().AllowUnmanaged()
+	name := app.Flag("name", "").Default("default").String()
+	app.Flag("verbose", "").Bool()
+	app.Flag("tag", "").Strings()
+	app.Flag("token", "").Secret().String()
+	app.Flag("level", "").Envar("TEST_RESULT_LEVEL").Enum("debug", "info")
+	server := app.Command("server", "")
+	timeout := server.Flag("timeout", "").Default("5s").Duration()
+	server.Flag("port", "").Required().Int()
+	server.Arg("address", "").Default("localhost").String()
+	app.Command("client", "")
+	return app, name, timeout
+}
+
+func TestParseResult(t *testing.T) {
+	os.Setenv("TEST_RESULT_LEVEL", "debug")
+	defer os.Unsetenv("TEST_RESULT_LEVEL")
+
+	app, name, timeout := newResultTestApp()
+	result, err := app.ParseResult([]string{"--name=joe", "--tag=a", "server", "--port", "80", "--tag", "b", "--token=s3cr3t", "--unknown"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "server", result.Command())
+	assert.Equal(t, []string{"server"}, result.CommandPath())
+	assert.Equal(t, "joe", result.String("name"))
+	assert.Equal(t, []string{"a", "b"}, result.Strings("tag"))
+	assert.False(t, result.Bool("verbose"))
+	assert.Equal(t, 80, result.Int("server.port"))
+	assert.Equal(t, 80, result.Int("port"))
+	assert.Equal(t, 5*time.Second, result.Duration("timeout"))
+	assert.Equal(t, "localhost", result.String("server.address"))
+	assert.Equal(t, "debug", result.String("level"))
+	assert.Equal(t, "s3cr3t", result.String("token"))
+	assert.Equal(t, []string{"--unknown"}, result.Unmanaged())
+	assert.Nil(t, result.Get("undefined"))
+
+	assert.Equal(t, Provenance{Source: SourceCommandLine, Values: []string{"joe"}}, result.Provenance("name"))
+	assert.Equal(t, Provenance{Source: SourceDefault, Values: []string{"5s"}}, result.Provenance("timeout"))
+	assert.Equal(t, Provenance{Source: SourceEnvar, Envar: "TEST_RESULT_LEVEL", Values: []string{"debug"}}, result.Provenance("level"))
+	assert.Equal(t, Provenance{Source: SourceCommandLine, Values: []string{secretMask}}, result.Provenance("token"))
+	assert.Equal(t, SourceUnset, result.Provenance("verbose").Source)
+	assert.True(t, result.IsSet("port"))
+	assert.False(t, result.IsSet("address"))
+
+	// The bound targets are not modified until the result is applied
+	assert.Equal(t, "", *name)
+	assert.Equal(t, time.Duration(0), *timeout)
+	assert.Empty(t, app.Unmanaged)
+	assert.NoError(t, result.Apply())
+	assert.Equal(t, "joe", *name)
+	assert.Equal(t, 5*time.Second, *timeout)
+	assert.Equal(t, []string{"--unknown"}, app.Unmanaged)
+}
+
+func TestParseResultErrors(t *testing.T) {
+	app, _, _ := newResultTestApp()
+	cases := []struct {
+		args []string
+		err  string
+	}{
+		{nil, "command not specified"},
+		{[]string{"server"}, "required flag(s) '--port' not provided"},
+		{[]string{"--name=a", "--name=b", "client"}, "flag 'name' cannot be repeated"},
+		{[]string{"--level=trace", "client"}, "enum value must be one of debug,info, got 'trace'"},
+		{[]string{"--token=x", "--token=y", "client"}, "flag 'token' cannot be repeated"},
+	}
+	for _, c := range cases {
+		_, err := app.ParseResult(c.args)
+		assert.EqualError(t, err, c.err, "%v", c.args)
+	}
+}
+
+func TestParseResultTrailingArgs(t *testing.T) {
+	app := newTestApp()
+	app.Arg("args", "").Strings()
+	result, err := app.ParseResult([]string{"a", "--", "-b", "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "-b", "c"}, result.Strings("args"))
+	assert.Equal(t, []string{"-b", "c"}, result.Trailing())
+}
+
+func TestParseResultCustomValue(t *testing.T) {
+	app := newTestApp()
+	var target []string
+	app.Flag("custom", "").SetValue((*stringsValue)(&target))
+	result, err := app.ParseResult([]string{"--custom=a", "--custom=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result.Get("custom"))
+	assert.Empty(t, target)
+}
+
+type stringsValue []string
+
+func (s *stringsValue) Set(value string) error { *s = append(*s, value); return nil }
+func (s *stringsValue) String() string         { return "" }
+func (s *stringsValue) IsCumulative() bool     { return true }
+
+func TestParseResultDoesNotFreeze(t *testing.T) {
+	app, name, _ := newResultTestApp()
+	_, err := app.ParseResult([]string{"--name=joe", "client", "--unknown"})
+	assert.NoError(t, err)
+	assert.False(t, app.IsFrozen())
+	assert.Equal(t, "", *name)
+	assert.Empty(t, app.Unmanaged)
+
+	_, err = app.Parse([]string{"--name=joe", "client", "--unknown"})
+	assert.NoError(t, err)
+	assert.Equal(t, "joe", *name)
+	assert.Equal(t, []string{"--unknown"}, app.Unmanaged)
+}
+
+func TestParseResultFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(filename, []byte("content"), 0600))
+	app := newTestApp()
+	input := app.Flag("input", "").File()
+
+	result, err := app.ParseResult([]string{"--input", filename})
+	assert.NoError(t, err)
+	file := result.Get("input").(*os.File)
+	assert.NoError(t, result.Close())
+	_, err = file.Stat()
+	assert.Error(t, err, "the file must be closed")
+
+	result, err = app.ParseResult([]string{"--input", filename})
+	assert.NoError(t, err)
+	file = result.Get("input").(*os.File)
+	assert.NoError(t, result.Apply())
+	assert.Same(t, file, *input, "the file must not be opened again")
+	assert.NoError(t, result.Close())
+	_, err = file.Stat()
+	assert.NoError(t, err, "the applied file must be owned by the application")
+	assert.NoError(t, app.Close())
+	_, err = file.Stat()
+	assert.Error(t, err)
+	assert.Nil(t, *input)
+}
+
+func TestParseResultConcurrent(t *testing.T) {
+	app, _, _ := newResultTestApp()
+	assert.NoError(t, app.Freeze())
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(port int) {
+			defer wg.Done()
+			result, err := app.ParseResult([]string{"server", "--port", string(rune('0' + port%10))})
+			if assert.NoError(t, err) {
+				assert.Equal(t, port%10, result.Int("port"))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,158 @@
+package kingpin
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SearchMatch is a command or a flag matching a search term.
+type SearchMatch struct {
+	Command string // Full command, empty for the flags of the application
+	Flag    string // Name of the matching flag, empty if the command matches
+	Snippet string // Excerpt of the matching text
+	Score   int    // Relevance of the match, higher is better
+}
+
+// Path returns the command line designating the match, such as
+// "deploy --timeout".
+func (m *SearchMatch) Path() string {
+	path := m.Command
+	if m.Flag != "" {
+		path = strings.TrimSpace(path + " --" + m.Flag)
+	}
+	return path
+}
+
+// Relevance of the matching fields.
+const (
+	scoreName     = 100
+	scoreAlias    = 80
+	scoreFlagName = 60
+	scoreHelp     = 40
+	scoreHelpLong = 20
+)
+
+// Search returns the visible commands and flags whose names, aliases or help
+// texts contain the term (ignoring the case), the most relevant first.
+func (a *ApplicationModel) Search(term string) []*SearchMatch {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil
+	}
+	var matches []*SearchMatch
+	searchFlags := func(command string, flags []*FlagModel) {
+		for _, flag := range flags {
+			if flag.Hidden {
+				continue
+			}
+			names := append([]string{flag.Name}, flag.Aliases...)
+			if match := searchFields(term, names, scoreFlagName, flag.Help, flag.HelpLong); match != nil {
+				if match.Snippet == "" {
+					match.Snippet = flag.Help
+				}
+				match.Command, match.Flag = command, flag.Name
+				matches = append(matches, match)
+			}
+		}
+	}
+	searchFlags("", a.Flags)
+	var walk func(cmds []*CmdModel)
+	walk = func(cmds []*CmdModel) {
+		for _, cmd := range cmds {
+			if cmd.Hidden {
+				continue
+			}
+			match := searchFields(term, []string{cmd.Name}, scoreName, "", "")
+			if match == nil {
+				match = searchFields(term, cmd.Aliases, scoreAlias, cmd.Help, cmd.HelpLong)
+			}
+			if match != nil {
+				if match.Snippet == "" {
+					match.Snippet = cmd.Help
+				}
+				match.Command = cmd.FullCommand
+				matches = append(matches, match)
+			}
+			searchFlags(cmd.FullCommand, cmd.Flags)
+			walk(cmd.Commands)
+		}
+	}
+	walk(a.Commands)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// Returns the best match of the term in the names or help texts, nil if none
+// of them contains the term. An exact name scores higher than a partial one
+// and the snippet is only set for the help texts.
+func searchFields(term string, names []string, nameScore int, help, helpLong string) *SearchMatch {
+	for _, name := range names {
+		if lower := strings.ToLower(name); lower == term {
+			return &SearchMatch{Score: nameScore + 10}
+		} else if strings.Contains(lower, term) {
+			return &SearchMatch{Score: nameScore}
+		}
+	}
+	if snippet, ok := searchSnippet(term, help); ok {
+		return &SearchMatch{Snippet: snippet, Score: scoreHelp}
+	}
+	if snippet, ok := searchSnippet(term, helpLong); ok {
+		return &SearchMatch{Snippet: snippet, Score: scoreHelpLong}
+	}
+	return nil
+}
+
+// Returns the excerpt of the text surrounding the first occurrence of the
+// term.
+func searchSnippet(term, text string) (string, bool) {
+	const context = 30
+	text = strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(text)
+	index := strings.Index(lower, term)
+	if index < 0 {
+		return "", false
+	}
+	if len(lower) != len(text) {
+		// The offsets in the lower case text do not match the original text
+		return text, true
+	}
+	start, end := index-context, index+len(term)+context
+	prefix, suffix := "...", "..."
+	if start <= 0 {
+		start, prefix = 0, ""
+	} else if space := strings.IndexByte(text[start:index], ' '); space >= 0 {
+		start += space + 1
+	}
+	if end >= len(text) {
+		end, suffix = len(text), ""
+	} else if space := strings.LastIndexByte(text[index+len(term):end], ' '); space >= 0 {
+		end = index + len(term) + space
+	}
+	return prefix + text[start:end] + suffix, true
+}
+
+// Prints the commands and flags matching the term and returns the exit
+// status.
+func (a *Application) writeSearch(term string) int {
+	if strings.TrimSpace(term) == "" {
+		a.Errorf(a.translate("expected a term to search"))
+		return 1
+	}
+	matches := a.Model().Search(term)
+	if len(matches) == 0 {
+		a.Errorf(a.translate("no command or flag matches %q"), term)
+		return 1
+	}
+	rows := [][2]string{}
+	for _, match := range matches {
+		rows = append(rows, [2]string{match.Path(), match.Snippet})
+	}
+	buf := bytes.NewBuffer(nil)
+	formatTwoColumns(buf, 0, 2, guessWidth(a.displayWriter()), rows)
+	fmt.Fprint(a.usageWriter, buf.String())
+	return 0
+}
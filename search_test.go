@@ -0,0 +1,61 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchApp(buf *bytes.Buffer) *Application {
+	app := newTestApp().Writer(buf).ErrorWriter(buf)
+	app.Terminate(func(status int) { panic(status) })
+	app.Flag("timeout", "Timeout of the network operations.").Duration()
+	cluster := app.Command("cluster", "Manage the clusters.")
+	nodes := cluster.Command("nodes", "Manage the nodes of a cluster.").Alias("node")
+	nodes.Command("drain", "Evict the workloads.").HelpLong("The workloads are moved to the other nodes before the node maintenance.")
+	nodes.Command("secret", "Secret command.").Hidden()
+	deploy := app.Command("deploy", "Deploy the application.")
+	deploy.Flag("node", "Target node of the deployment.").String()
+	return app
+}
+
+func TestSearch(t *testing.T) {
+	app := newSearchApp(&bytes.Buffer{})
+	assert.NoError(t, app.init())
+	var paths, snippets []string
+	for _, match := range app.Model().Search("Node") {
+		paths = append(paths, match.Path())
+		snippets = append(snippets, match.Snippet)
+	}
+	assert.Equal(t, []string{"cluster nodes", "deploy --node", "cluster nodes drain"}, paths, "names first, then help texts")
+	assert.Equal(t, []string{
+		"Manage the nodes of a cluster.",
+		"Target node of the deployment.",
+		"...are moved to the other nodes before the node maintenance.",
+	}, snippets)
+
+	assert.Empty(t, app.Model().Search("secret"), "hidden commands are ignored")
+	assert.Empty(t, app.Model().Search(" "))
+}
+
+func TestHelpSearch(t *testing.T) {
+	var buf bytes.Buffer
+	app := newSearchApp(&buf)
+	assert.Equal(t, 0, parseHelp(app, "help", "--search", "network"))
+	assert.Equal(t, "--timeout  Timeout of the network operations.\n", buf.String())
+
+	buf.Reset()
+	app = newSearchApp(&buf)
+	assert.Equal(t, 1, parseHelp(app, "help", "--search=unknown"))
+	assert.Equal(t, "test: error: no command or flag matches \"unknown\"\n", buf.String())
+}
+
+func TestHelpSearchTerminatesOnce(t *testing.T) {
+	var buf bytes.Buffer
+	app := newSearchApp(&buf)
+	var statuses []int
+	app.Terminate(func(status int) { statuses = append(statuses, status) })
+	_, _ = app.Parse([]string{"help", "--search=unknown"})
+	assert.Equal(t, []int{1}, statuses)
+}
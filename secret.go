@@ -0,0 +1,134 @@
+package kingpin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Mask displayed instead of secret values.
+const secretMask = "******"
+
+// Secret marks the flag value as sensitive. The value is redacted in usage,
+// models and error messages, and it is not echoed when the user is prompted
+// for it.
+//
+// A companion flag --<name>-file is automatically added (unless a flag with
+// that name already exists) to read the value from a file, or from stdin if
+// the file name is "-".
+func (f *FlagClause) Secret() *FlagClause {
+	f.secret = true
+	return f
+}
+
+// Sets the flag value, ensuring that the errors do not disclose secret values.
+func (f *FlagClause) setValue(value string) error {
+	err := f.value.Set(value)
+	if err != nil && f.secret {
+		return redactError(err, f.name, value)
+	}
+	return err
+}
+
+// Error of a secret flag with a generic message, since the message of the
+// wrapped error may disclose the value.
+type secretError struct {
+	flag string
+	err  error
+}
+
+// Message of secretError, formatted with the mask and the flag name.
+const secretErrorFormat = "invalid value %s for flag '--%s'"
+
+func (e *secretError) Error() string { return fmt.Sprintf(secretErrorFormat, secretMask, e.flag) }
+func (e *secretError) Unwrap() error { return e.err }
+
+// Returns an error that does not disclose the value of the secret flag. The
+// arguments of a translatable error which hold the value are masked, and the
+// other errors are wrapped in a secretError.
+func redactError(err error, flag, value string) error {
+	if value == "" {
+		return err
+	}
+	e, ok := err.(*translatableError)
+	if !ok {
+		return &secretError{flag: flag, err: err}
+	}
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		switch a := arg.(type) {
+		case string:
+			if a == value {
+				arg = secretMask
+			}
+		case error:
+			if strings.Contains(a.Error(), value) {
+				arg = secretMask
+			}
+		}
+		args[i] = arg
+	}
+	return &translatableError{format: e.format, args: args}
+}
+
+// Add the --<name>-file companion flags of secret flags.
+func (f *flagGroup) addSecretFileFlags() {
+	for _, flag := range f.flagOrder {
+		if !flag.secret || flag.secretFile != nil {
+			continue
+		}
+		name := flag.name + "-file"
+		if _, exist := f.long[name]; exist {
+			continue
+		}
+		flag.secretFile = f.Flag(name, fmt.Sprintf("Read the value of --%s from a file (- for stdin).", flag.name))
+		flag.secretFile.secretOf = flag
+		flag.secretFile.hidden = flag.hidden
+		flag.secretFile.PlaceHolder("FILE").SetValue(&secretFileValue{flag: flag})
+	}
+}
+
+// Returns the flag that is actually set when the supplied flag is used.
+func (f *FlagClause) target() *FlagClause {
+	if f.secretOf != nil {
+		return f.secretOf
+	}
+	return f
+}
+
+// -- secret file Value
+
+type secretFileValue struct {
+	flag *FlagClause
+	path string
+}
+
+// Set loads the file outside of a parse, where stdin is not available: the
+// parses use load with the stdin of the application.
+func (s *secretFileValue) Set(path string) error {
+	return s.load(path, nil)
+}
+
+// Sets the secret flag to the content of the file, or of stdin if path is -.
+func (s *secretFileValue) load(path string, stdin io.Reader) error {
+	content, err := readValueFile(path, DefaultFileValueSizeLimit, stdin)
+	if err != nil {
+		return fmt.Errorf("unable to read value of '--%s': %s", s.flag.name, err)
+	}
+	s.path = path
+	err = s.flag.setValue(string(bytes.TrimRight(content, "\r\n")))
+	// Clear the raw content since it is no longer needed
+	for i := range content {
+		content[i] = 0
+	}
+	return err
+}
+
+func (s *secretFileValue) Get() interface{} { return s.path }
+
+func (s *secretFileValue) String() string { return s.path }
+
+func (s *secretFileValue) Reset() { s.path = "" }
+
+func (s *secretFileValue) acceptsStdio() {}
@@ -0,0 +1,148 @@
+package kingpin
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRedactedInUsage(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().Writer(&buf)
+	app.Flag("token", "API token.").Secret().Default("s3cr3t").String()
+	app.Flag("user", "User.").Default("joe").String()
+
+	for _, template := range []string{DefaultUsageTemplate, LongHelpTemplate, ManPageTemplate, CompactUsageTemplate} {
+		buf.Reset()
+		app.UsageTemplate(template)
+		app.Usage(nil)
+		assert.NotContains(t, buf.String(), "s3cr3t")
+		assert.Contains(t, buf.String(), "--token=TOKEN")
+		assert.Contains(t, buf.String(), `--user="joe"`)
+	}
+}
+
+func TestSecretRedactedInModel(t *testing.T) {
+	app := newTestApp()
+	token := app.Flag("token", "").Secret().Default("s3cr3t").String()
+	_, err := app.Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", *token)
+
+	model := app.GetFlag("token").Model()
+	assert.True(t, model.Secret)
+	assert.Equal(t, []string{secretMask}, model.Default)
+	assert.Equal(t, secretMask, model.String())
+	assert.Equal(t, []string{"s3cr3t"}, app.GetFlag("token").defaultValues)
+}
+
+func TestSecretRedactedInErrors(t *testing.T) {
+	app := newTestApp()
+	app.Flag("pin", "").Secret().Int()
+	_, err := app.Parse([]string{"--pin=12ab34"})
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "12ab34")
+	assert.Contains(t, err.Error(), secretMask)
+
+	os.Setenv("TEST_SECRET_PIN", "98xy76")
+	defer os.Unsetenv("TEST_SECRET_PIN")
+	app = newTestApp()
+	app.Flag("pin", "").Secret().Envar("TEST_SECRET_PIN").Int()
+	_, err = app.Parse(nil)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "98xy76")
+}
+
+func TestSecretRedactedWithoutMangling(t *testing.T) {
+	app := newTestApp()
+	app.Flag("level", "").Secret().Enum("a", "b")
+	_, err := app.Parse([]string{"--level=e"})
+	assert.EqualError(t, err, "enum value must be one of a,b, got '"+secretMask+"'")
+
+	app = newTestApp()
+	app.Flag("pin", "").Secret().Int()
+	_, err = app.Parse([]string{"--pin=1x"})
+	assert.EqualError(t, err, "invalid value "+secretMask+" for flag '--pin'")
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+
+	app = newTestApp().Messages(Translations{secretErrorFormat: "valeur %s invalide pour l'option '--%s'"})
+	app.Flag("pin", "").Secret().Int()
+	_, err = app.Parse([]string{"--pin=1x"})
+	assert.EqualError(t, err, "valeur "+secretMask+" invalide pour l'option '--pin'")
+	assert.True(t, errors.As(err, &numErr))
+
+	app = newTestApp()
+	app.Flag("pin", "").Secret().Int()
+	_, err = app.Parse([]string{"--pin="})
+	assert.EqualError(t, err, `strconv.ParseFloat: parsing "": invalid syntax`)
+}
+
+func TestSecretClearedFromContext(t *testing.T) {
+	app := newTestApp()
+	app.Flag("token", "").Secret().String()
+	var values []string
+	app.Action(func(context *ParseContext) error {
+		for _, element := range context.Elements {
+			values = append(values, *element.Value)
+		}
+		return nil
+	})
+	_, err := app.Parse([]string{"--token=s3cr3t"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, values)
+}
+
+func TestSecretFromFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(filename, []byte("s3cr3t\n"), 0600))
+
+	app := newTestApp()
+	token := app.Flag("token", "").Secret().Required().String()
+	_, err := app.Parse([]string{"--token-file", filename})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", *token)
+
+	_, err = app.Parse([]string{"--token-file", filename + ".missing"})
+	assert.Error(t, err)
+}
+
+func TestSecretFromStdin(t *testing.T) {
+	app := newTestApp()
+	cmd := app.Command("login", "")
+	token := cmd.Flag("token", "").Secret().String()
+
+	_, err := app.Stdin(strings.NewReader("from-stdin\r\n")).Parse([]string{"login", "--token-file=-"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from-stdin", *token)
+
+	_, err = app.Stdin(strings.NewReader("next\n")).Parse([]string{"login", "--token-file", "-"})
+	assert.NoError(t, err)
+	assert.Equal(t, "next", *token)
+}
+
+func TestSecretFileFromEnvarStdin(t *testing.T) {
+	t.Setenv("TEST_TOKEN_FILE", "-")
+	app := newTestApp().DefaultEnvars()
+	token := app.Flag("token", "").Secret().String()
+	_, err := app.Stdin(strings.NewReader("from-envar\n")).Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-envar", *token)
+
+	assert.EqualError(t, app.GetFlag("token-file").value.Set("-"), "unable to read value of '--token': stdin is not available")
+}
+
+func TestSecretFileFlagNotOverridden(t *testing.T) {
+	app := newTestApp()
+	app.Flag("token", "").Secret().String()
+	file := app.Flag("token-file", "Custom flag.").String()
+	_, err := app.Parse([]string{"--token-file=custom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", *file)
+}
@@ -0,0 +1,151 @@
+package kingpin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// RunShell reads command lines from in and dispatches each of them to the
+// application as if it was invoked with the corresponding arguments, until the
+// end of the input or until the exit (or quit) command is entered. The
+// application outputs (usage and errors) are redirected to out.
+//
+// Lines are split using the shell syntax (see ArgsFileShell) and the values
+// are reset (see Application.Reset) before each line. A line ending with a tab
+// prints the completion options instead of being executed. The history
+// command lists the previous lines and help is available even if the
+// application has no command.
+//
+// RunShell can be used to provide an interactive mode through a command, once
+// the command line has been parsed:
+//
+//	shell := app.Command("shell", "Start an interactive shell.")
+//	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+//	case shell.FullCommand():
+//		app.FatalIfError(kingpin.RunShell(app, os.Stdin, os.Stdout), "")
+//	}
+//
+// Since each line resets the values and parses the application again, RunShell
+// fails if it is called while the application is parsing, such as from an
+// action.
+func RunShell(app *Application, in io.Reader, out io.Writer) error {
+	if atomic.LoadInt32(&app.parsing) > 0 {
+		return fmt.Errorf("RunShell cannot be called while the application is parsing")
+	}
+	if err := app.init(); err != nil {
+		return err
+	}
+	s := &shell{app: app, out: out}
+	defer func(terminate func(int), usageWriter, errorWriter io.Writer) {
+		app.terminate, app.usageWriter, app.errorWriter = terminate, usageWriter, errorWriter
+	}(app.terminate, app.usageWriter, app.errorWriter)
+	app.terminate = func(status int) { panic(shellTerminated(status)) }
+	app.usageWriter, app.errorWriter = out, out
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "%s> ", app.Name)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		if !s.run(strings.TrimRight(scanner.Text(), "\r")) {
+			return nil
+		}
+	}
+}
+
+// Raised by the application terminate function to stop the current line.
+type shellTerminated int
+
+type shell struct {
+	app     *Application
+	out     io.Writer
+	history []string
+}
+
+// Executes a line and returns false if the shell must exit.
+func (s *shell) run(line string) bool {
+	if strings.HasSuffix(line, "\t") {
+		s.complete(strings.TrimRight(line, "\t"))
+		return true
+	}
+	args, err := s.split(line)
+	if err != nil {
+		s.app.Errorf("%s", err)
+		return true
+	}
+	if len(args) == 0 {
+		return true
+	}
+	s.history = append(s.history, line)
+
+	if s.app.GetCommand(args[0]) == nil {
+		switch args[0] {
+		case "exit", "quit":
+			return false
+		case "history":
+			for i, line := range s.history {
+				fmt.Fprintf(s.out, "%5d  %s\n", i+1, line)
+			}
+			return true
+		case "help":
+			args = append([]string{"--help"}, args[1:]...)
+		}
+	}
+
+	if err := s.dispatch(args); err != nil {
+		s.app.Errorf("%s", err)
+	}
+	return true
+}
+
+func (s *shell) dispatch(args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(shellTerminated); !ok {
+				panic(r)
+			}
+		}
+	}()
+	s.app.Reset()
+	_, err = s.app.Parse(args)
+	return
+}
+
+// Prints the completion options of the last word of the line.
+func (s *shell) complete(line string) {
+	args, err := s.split(line)
+	if err != nil {
+		return
+	}
+	if len(args) == 0 || strings.HasSuffix(line, " ") {
+		args = append(args, "")
+	}
+	context, _ := s.app.ParseContext(append([]string{"--completion-bash"}, args...))
+	if context == nil {
+		return
+	}
+	current := args[len(args)-1]
+	for _, option := range s.app.completionOptions(context) {
+		if strings.HasPrefix(option, current) {
+			fmt.Fprintln(s.out, option)
+		}
+	}
+}
+
+func (s *shell) split(line string) ([]string, error) {
+	words, err := splitShellWords(line, s.app.fileExpansion.resolve().prefix, os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, len(words))
+	for _, word := range words {
+		args = append(args, word.value)
+	}
+	return args, nil
+}
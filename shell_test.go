@@ -0,0 +1,90 @@
+package kingpin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunShell(t *testing.T) {
+	app := newTestApp()
+	var calls []string
+	greet := app.Command("greet", "Greet someone.")
+	names := greet.Arg("name", "").Strings()
+	loud := greet.Flag("loud", "").Bool()
+	greet.Action(func(*ParseContext) error {
+		calls = append(calls, strings.Join(*names, "+")+"/"+map[bool]string{true: "loud", false: "quiet"}[*loud])
+		return nil
+	})
+	app.Command("list", "List things.")
+
+	input := strings.Join([]string{
+		`greet "John Smith" Jane --loud`,
+		`greet Joe`,
+		``,
+		`greet 'unterminated`,
+		`unknown`,
+		`help greet`,
+		`history`,
+		`greet --lo` + "\t",
+		`l` + "\t",
+		`exit`,
+		`greet never`,
+	}, "\n")
+	var out bytes.Buffer
+	assert.NoError(t, RunShell(app, strings.NewReader(input), &out))
+
+	assert.Equal(t, []string{"John Smith+Jane/loud", "Joe/quiet"}, calls)
+	output := out.String()
+	assert.Contains(t, output, "test> ")
+	assert.Contains(t, output, "test: error: line 1: unterminated single quote")
+	assert.Contains(t, output, "test: error: expected command but got \"unknown\"")
+	assert.Contains(t, output, "usage: test greet [<flags>] [<name>...]")
+	assert.Contains(t, output, "    1  greet \"John Smith\" Jane --loud\n    2  greet Joe\n")
+	assert.Contains(t, output, "test> --loud\n")
+	assert.Contains(t, output, "test> list\n")
+	assert.NotContains(t, output, "never")
+}
+
+func TestRunShellWithoutCommands(t *testing.T) {
+	app := newTestApp()
+	app.Flag("name", "The name.").String()
+	var out bytes.Buffer
+	assert.NoError(t, RunShell(app, strings.NewReader("help\n--name joe"), &out))
+	assert.Contains(t, out.String(), "--name=NAME  The name.")
+	assert.True(t, strings.HasSuffix(out.String(), "test> \n"))
+}
+
+func TestRunShellAfterParse(t *testing.T) {
+	app := newTestApp()
+	shell := app.Command("shell", "Start an interactive shell.")
+	greet := app.Command("greet", "")
+	name := greet.Arg("name", "").String()
+	var greeted []string
+	greet.Action(func(*ParseContext) error {
+		greeted = append(greeted, *name)
+		return nil
+	})
+	command, err := app.Parse([]string{"shell"})
+	assert.NoError(t, err)
+	assert.Equal(t, shell.FullCommand(), command)
+
+	var out bytes.Buffer
+	assert.NoError(t, RunShell(app, strings.NewReader("greet joe\nshell"), &out))
+	assert.Equal(t, []string{"joe"}, greeted)
+	assert.NotContains(t, out.String(), "error")
+}
+
+func TestRunShellDuringParse(t *testing.T) {
+	app := newTestApp()
+	var shellErr error
+	app.Command("shell", "").Action(func(*ParseContext) error {
+		shellErr = RunShell(app, strings.NewReader("shell"), &bytes.Buffer{})
+		return nil
+	})
+	_, err := app.Parse([]string{"shell"})
+	assert.NoError(t, err)
+	assert.EqualError(t, shellErr, "RunShell cannot be called while the application is parsing")
+}
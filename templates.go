@@ -1,15 +1,31 @@
 package kingpin
 
+// Templates shared by the usage templates.
+const usageDefinitions = `{{define "FormatExamples" -}}
+{{range .}}  {{.Command|StyleCommand}}
+{{.Help|Wrap 4}}
+{{end -}}
+{{end -}}
+
+{{define "FormatUserAliases" -}}
+{{with . -}}
+{{T "Aliases"|printf "%s:"|StyleHeading}}
+{{.|UserAliasesToTwoColumns|FormatTwoColumns}}
+{{end -}}
+{{end -}}
+
+`
+
 // Default usage template.
-var DefaultUsageTemplate = `{{define "FormatCommand" -}}
+var DefaultUsageTemplate = usageDefinitions + `{{define "FormatCommand" -}}
 {{if .FlagSummary}} {{.FlagSummary}}{{end -}}
 {{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
 {{end -}}
 
 {{define "FormatCommands" -}}
 {{range .FlattenedCommands -}}
-{{if not .Hidden -}}
-  {{.FullCommand}}{{if .Default}}*{{end}}{{template "FormatCommand" .}}
+{{if not (or .Hidden .Deprecated) -}}
+  {{.FullCommand|StyleCommand}}{{if .Default}}*{{end}}{{template "FormatCommand" .}}
 {{.Help|Wrap 4}}
 {{end -}}
 {{end -}}
@@ -24,39 +40,54 @@ var DefaultUsageTemplate = `{{define "FormatCommand" -}}
 {{end -}}
 
 {{if .Context.SelectedCommand -}}
-usage: {{.App.Name}} {{.Context.SelectedCommand}}{{template "FormatUsage" .Context.SelectedCommand}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}} {{.Context.SelectedCommand}}{{template "FormatUsage" .Context.SelectedCommand}}
 {{ else -}}
-usage: {{.App.Name}}{{template "FormatUsage" .App}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}}{{template "FormatUsage" .App}}
 {{end}}
-{{if .Context.Flags -}}
-Flags:
-{{.Context.Flags|FlagsToTwoColumns|FormatTwoColumns}}
+{{range .Context.Flags|FlagCategories -}}
+{{or .Name "Flags"|T|printf "%s:"|StyleHeading}}
+{{.Flags|FlagsToTwoColumns|FormatTwoColumns}}
 {{end -}}
 {{if .Context.Args -}}
-Args:
+{{T "Args"|printf "%s:"|StyleHeading}}
 {{.Context.Args|ArgsToTwoColumns|FormatTwoColumns}}
 {{end -}}
 {{if .Context.SelectedCommand -}}
 {{if len .Context.SelectedCommand.Commands -}}
-Subcommands:
-{{template "FormatCommands" .Context.SelectedCommand}}
+{{range .Context.SelectedCommand.CmdGroupModel|CommandCategories -}}
+{{if .FlattenedCommands -}}
+{{or .Name "Subcommands"|T|printf "%s:"|StyleHeading}}
+{{template "FormatCommands" .}}
+{{end -}}
+{{end -}}
 {{end -}}
 {{else if .App.Commands -}}
-Commands:
-{{template "FormatCommands" .App}}
+{{range .App.CmdGroupModel|CommandCategories -}}
+{{if .FlattenedCommands -}}
+{{or .Name "Commands"|T|printf "%s:"|StyleHeading}}
+{{template "FormatCommands" .}}
+{{end -}}
+{{end -}}
+{{end -}}
+{{if not .Context.SelectedCommand -}}
+{{template "FormatUserAliases" .App.UserAliases -}}
+{{end -}}
+{{with (or .Context.SelectedCommand .App).Examples -}}
+{{T "Examples"|printf "%s:"|StyleHeading}}
+{{template "FormatExamples" .}}
 {{end -}}
 `
 
 // Usage template where command's optional flags are listed separately
-var SeparateOptionalFlagsUsageTemplate = `{{define "FormatCommand" -}}
+var SeparateOptionalFlagsUsageTemplate = usageDefinitions + `{{define "FormatCommand" -}}
 {{if .FlagSummary}} {{.FlagSummary}}{{end -}}
 {{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
 {{end -}}
 
 {{define "FormatCommands" -}}
 {{range .FlattenedCommands -}}
-{{if not .Hidden -}}
-  {{.FullCommand}}{{if .Default}}*{{end}}{{template "FormatCommand" .}}
+{{if not (or .Hidden .Deprecated) -}}
+  {{.FullCommand|StyleCommand}}{{if .Default}}*{{end}}{{template "FormatCommand" .}}
 {{.Help|Wrap 4}}
 {{end -}}
 {{end -}}
@@ -70,44 +101,56 @@ var SeparateOptionalFlagsUsageTemplate = `{{define "FormatCommand" -}}
 
 {{end -}}
 {{if .Context.SelectedCommand -}}
-usage: {{.App.Name}} {{.Context.SelectedCommand}}{{template "FormatUsage" .Context.SelectedCommand}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}} {{.Context.SelectedCommand}}{{template "FormatUsage" .Context.SelectedCommand}}
 {{else -}}
-usage: {{.App.Name}}{{template "FormatUsage" .App}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}}{{template "FormatUsage" .App}}
 {{end -}}
 
-{{if .Context.Flags|RequiredFlags -}}
-Required flags:
-{{.Context.Flags|RequiredFlags|FlagsToTwoColumns|FormatTwoColumns}}
+{{range .Context.Flags|RequiredFlags|FlagCategories -}}
+{{if .Name}}{{printf (T "%s (required)") .Name|printf "%s:"|StyleHeading}}{{else}}{{T "Required flags"|printf "%s:"|StyleHeading}}{{end}}
+{{.Flags|FlagsToTwoColumns|FormatTwoColumns}}
 {{end -}}
-{{if  .Context.Flags|OptionalFlags -}}
-Optional flags:
-{{.Context.Flags|OptionalFlags|FlagsToTwoColumns|FormatTwoColumns}}
+{{range .Context.Flags|OptionalFlags|FlagCategories -}}
+{{or .Name "Optional flags"|T|printf "%s:"|StyleHeading}}
+{{.Flags|FlagsToTwoColumns|FormatTwoColumns}}
 {{end -}}
 {{if .Context.Args -}}
-Args:
+{{T "Args"|printf "%s:"|StyleHeading}}
 {{.Context.Args|ArgsToTwoColumns|FormatTwoColumns}}
 {{end -}}
 {{if .Context.SelectedCommand -}}
-Subcommands:
+{{T "Subcommands"|printf "%s:"|StyleHeading}}
 {{if .Context.SelectedCommand.Commands -}}
-{{template "FormatCommands" .Context.SelectedCommand}}
+{{range .Context.SelectedCommand.CmdGroupModel|CommandCategories -}}
+{{if .FlattenedCommands -}}
+{{if .Name}}{{printf "%s:" .Name|StyleHeading}}
+{{end}}{{template "FormatCommands" .}}
+{{end -}}
+{{end -}}
 {{end -}}
 {{else if .App.Commands -}}
-Commands:
-{{template "FormatCommands" .App}}
+{{range .App.CmdGroupModel|CommandCategories -}}
+{{if .FlattenedCommands -}}
+{{or .Name "Commands"|T|printf "%s:"|StyleHeading}}
+{{template "FormatCommands" .}}
+{{end -}}
+{{end -}}
+{{end -}}
+{{if not .Context.SelectedCommand -}}
+{{template "FormatUserAliases" .App.UserAliases -}}
 {{end -}}
 `
 
 // Usage template with compactly formatted commands.
-var CompactUsageTemplate = `{{define "FormatCommand" -}}
+var CompactUsageTemplate = usageDefinitions + `{{define "FormatCommand" -}}
 {{if .FlagSummary}} {{.FlagSummary}}{{end -}}
 {{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
 {{end -}}
 
 {{define "FormatCommandList" -}}
 {{range . -}}
-{{if not .Hidden -}}
-{{.Depth|Indent}}{{.Name}}{{if .Default}}*{{end}}{{template "FormatCommand" .}}
+{{if not (or .Hidden .Deprecated) -}}
+{{.Depth|Indent}}{{.Name|StyleCommand}}{{if .Default}}*{{end}}{{template "FormatCommand" .}}
 {{end -}}
 {{template "FormatCommandList" .Commands -}}
 {{end -}}
@@ -122,53 +165,89 @@ var CompactUsageTemplate = `{{define "FormatCommand" -}}
 {{end -}}
 
 {{if .Context.SelectedCommand -}}
-usage: {{.App.Name}} {{.Context.SelectedCommand}}{{template "FormatUsage" .Context.SelectedCommand}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}} {{.Context.SelectedCommand}}{{template "FormatUsage" .Context.SelectedCommand}}
 {{else -}}
-usage: {{.App.Name}}{{template "FormatUsage" .App}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}}{{template "FormatUsage" .App}}
 {{end -}}
-{{if .Context.Flags -}}
-Flags:
-{{.Context.Flags|FlagsToTwoColumns|FormatTwoColumns}}
+{{range .Context.Flags|FlagCategories -}}
+{{or .Name "Flags"|T|printf "%s:"|StyleHeading}}
+{{.Flags|FlagsToTwoColumns|FormatTwoColumns}}
 {{end -}}
 {{if .Context.Args -}}
-Args:
+{{T "Args"|printf "%s:"|StyleHeading}}
 {{.Context.Args|ArgsToTwoColumns|FormatTwoColumns}}
 {{end -}}
 {{if .Context.SelectedCommand -}}
 {{if .Context.SelectedCommand.Commands -}}
-Commands:
+{{T "Commands"|printf "%s:"|StyleHeading}}
   {{.Context.SelectedCommand}}
-{{template "FormatCommandList" .Context.SelectedCommand.Commands}}
+{{range .Context.SelectedCommand.CmdGroupModel|CommandCategories -}}
+{{if .Commands -}}
+{{if .Name}}{{printf "%s:" .Name|StyleHeading}}
+{{end}}{{template "FormatCommandList" .Commands}}
+{{end -}}
+{{end -}}
 {{end -}}
 {{else if .App.Commands -}}
-Commands:
-{{template "FormatCommandList" .App.Commands}}
+{{range .App.CmdGroupModel|CommandCategories -}}
+{{if .Commands -}}
+{{or .Name "Commands"|T|printf "%s:"|StyleHeading}}
+{{template "FormatCommandList" .Commands}}
+{{end -}}
+{{end -}}
+{{end -}}
+{{if not .Context.SelectedCommand -}}
+{{template "FormatUserAliases" .App.UserAliases -}}
 {{end -}}
 `
 
-var ManPageTemplate = `{{define "FormatFlags" -}}
+// Templates shared by the man pages of the application and of the commands.
+const manPageDefinitions = `{{define "FormatFlags" -}}
 {{range .Flags -}}
 {{if not .Hidden -}}
 .TP
-\fB{{if .Short}}-{{.Short|Char}}, {{end}}--{{.Name}}{{if not .IsBoolFlag}}={{.FormatPlaceHolder}}{{end -}}\fR
-{{.Help}}
+\fB{{if .Short}}-{{.Short|Char}}, {{end}}--{{.Name}}{{if not .IsBoolFlag}}={{.FormatPlaceHolder|Roff}}{{end -}}\fR
+{{.Help|Roff}}
+{{with .HelpLong}}.IP
+{{.|Roff}}
+{{end -}}
+{{with DeprecationNotice .}}{{.|Roff}}
+{{end -}}
+{{end -}}
+{{end -}}
+{{end -}}
+
+{{define "FormatArgs" -}}
+{{range .Args -}}
+{{if not .Hidden -}}
+.TP
+\fB{{if .PlaceHolder}}{{.PlaceHolder|Roff}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}\fR
+{{.Help|Roff}}
+{{with .HelpLong}}.IP
+{{.|Roff}}
+{{end -}}
+{{with DeprecationNotice .}}{{.|Roff}}
+{{end -}}
 {{end -}}
 {{end -}}
 {{end -}}
 
 {{define "FormatCommand" -}}
-{{if .FlagSummary}} {{.FlagSummary}}{{end -}}
-{{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
+{{if .FlagSummary}} {{.FlagSummary|Roff}}{{end -}}
+{{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder|Roff}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
 {{end -}}
 
 {{define "FormatCommands" -}}
 {{range .FlattenedCommands -}}
 {{if not .Hidden -}}
 .SS
-\fB{{.FullCommand}}{{template "FormatCommand" . -}}\fR
+\fB{{.FullCommand|Roff}}{{template "FormatCommand" . -}}\fR
 .PP
-{{.Help}}
+{{.Help|Roff}}
+{{with DeprecationNotice .}}{{.|Roff}}
+{{end -}}
 {{template "FormatFlags" . -}}
+{{template "FormatArgs" . -}}
 {{end -}}
 {{end -}}
 {{end -}}
@@ -177,24 +256,116 @@ var ManPageTemplate = `{{define "FormatFlags" -}}
 {{template "FormatCommand" .}}{{if .Commands}} <command> [<args> ...]{{end -}}\fR
 {{end -}}
 
-.TH {{.App.Name}} 1 {{.App.Version}} "{{.App.Author}}"
-.SH "NAME"
+{{define "FormatEnvironment" -}}
+{{with EnvironmentFlags .Flags .Commands -}}
+.SH "{{T "ENVIRONMENT"}}"
+{{range . -}}
+.TP
+\fB{{.Envar|Roff}}\fR
+{{T "Value of"}} \fB--{{.Name}}\fR. {{.Help|Roff}}
+{{end -}}
+{{end -}}
+{{end -}}
+
+{{define "FormatExamples" -}}
+{{with . -}}
+.SH "{{T "EXAMPLES"}}"
+{{range . -}}
+.TP
+\fB{{.Command|Roff}}\fR
+{{.Help|Roff}}
+{{end -}}
+{{end -}}
+{{end -}}
+
+{{define "FormatExitStatus" -}}
+.SH "{{T "EXIT STATUS"}}"
+\fB{{.Name}}\fR {{T "exits with status 0 on success, and greater than 0 if an error occurs."}}
+{{end -}}
+`
+
+// ManPageTemplate is the template of the man page of the application,
+// displayed by --help-man and written by WriteManPages.
+var ManPageTemplate = manPageDefinitions + `.TH {{.App.Name}} {{.App.ManSection}} "{{.App.ManDate|Roff}}" "{{.App.Name}}{{with .App.Version}} {{.|Roff}}{{end}}" "{{.App.Author|Roff}}"
+.SH "{{T "NAME"}}"
 {{.App.Name}}
-.SH "SYNOPSIS"
+.SH "{{T "SYNOPSIS"}}"
 .TP
 \fB{{.App.Name}}{{template "FormatUsage" .App}}
-.SH "DESCRIPTION"
-{{.App.Help}}
-.SH "OPTIONS"
-{{template "FormatFlags" .App -}}
+.SH "{{T "DESCRIPTION"}}"
+{{.App.Help|Roff}}
+{{range .App.Flags|AllFlagCategories -}}
+.SH "{{if .Name}}{{.Name|Roff}}{{else}}{{T "OPTIONS"}}{{end}}"
+{{template "FormatFlags" . -}}
+{{end -}}
+{{if .App.Args -}}
+.SH "{{T "ARGUMENTS"}}"
+{{template "FormatArgs" .App -}}
+{{end -}}
 {{if .App.Commands -}}
-.SH "COMMANDS"
-{{template "FormatCommands" .App -}}
+{{range .App.CmdGroupModel|CommandCategories -}}
+{{if .FlattenedCommands -}}
+.SH "{{if .Name}}{{.Name|Roff}}{{else}}{{T "COMMANDS"}}{{end}}"
+{{template "FormatCommands" . -}}
+{{end -}}
+{{end -}}
+{{end -}}
+{{template "FormatEnvironment" .App -}}
+{{template "FormatExamples" .App.AllExamples -}}
+{{template "FormatExitStatus" .App -}}
+{{if .ManPages -}}
+{{with ManPageCommands .App.Commands -}}
+.SH "{{T "SEE ALSO"}}"
+{{range $i, $cmd := .}}{{if $i}},
+{{end}}\fB{{ManPageName $cmd}}\fR({{$.App.ManSection}}){{end}}
+{{end -}}
+{{end -}}
+`
+
+// ManCommandPageTemplate is the template of the man page of a command, written
+// by WriteManPages.
+var ManCommandPageTemplate = manPageDefinitions + `{{with .Context.SelectedCommand -}}
+.TH {{ManPageName .}} {{$.App.ManSection}} "{{$.App.ManDate|Roff}}" "{{$.App.Name}}{{with $.App.Version}} {{.|Roff}}{{end}}" "{{$.App.Author|Roff}}"
+.SH "{{T "NAME"}}"
+{{ManPageName .}}{{with .Help}} \- {{.|Roff}}{{end}}
+.SH "{{T "SYNOPSIS"}}"
+.TP
+\fB{{$.App.Name}} {{.FullCommand|Roff}}{{template "FormatUsage" .}}
+.SH "{{T "DESCRIPTION"}}"
+{{.Help|Roff}}
+{{with .HelpLong}}.PP
+{{.|Roff}}
+{{end -}}
+{{with DeprecationNotice .}}.PP
+{{.|Roff}}
+{{end -}}
+{{if .Flags -}}
+.SH "{{T "OPTIONS"}}"
+{{template "FormatFlags" . -}}
+{{end -}}
+{{if .Args -}}
+.SH "{{T "ARGUMENTS"}}"
+{{template "FormatArgs" . -}}
+{{end -}}
+{{if $.App.Flags -}}
+.SH "{{T "GLOBAL OPTIONS"}}"
+{{template "FormatFlags" $.App -}}
+{{end -}}
+{{if .Commands -}}
+.SH "{{T "COMMANDS"}}"
+{{template "FormatCommands" . -}}
+{{end -}}
+{{template "FormatEnvironment" . -}}
+{{template "FormatExamples" .Examples -}}
+{{template "FormatExitStatus" $.App -}}
+.SH "{{T "SEE ALSO"}}"
+\fB{{$.App.Name}}\fR({{$.App.ManSection}}){{range ManPageCommands .Commands}},
+\fB{{ManPageName .}}\fR({{$.App.ManSection}}){{end}}
 {{end -}}
 `
 
 // Default usage template.
-var LongHelpTemplate = `{{define "FormatCommand" -}}
+var LongHelpTemplate = usageDefinitions + `{{define "FormatCommand" -}}
 {{if .FlagSummary}} {{.FlagSummary}}{{end -}}
 {{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
 {{end -}}
@@ -202,9 +373,10 @@ var LongHelpTemplate = `{{define "FormatCommand" -}}
 {{define "FormatCommands" -}}
 {{range .FlattenedCommands -}}
 {{if not .Hidden -}}
-  {{.FullCommand}}{{template "FormatCommand" .}}
-{{.Help|Wrap 4}}
-{{with .Flags|FlagsToTwoColumns}}{{FormatTwoColumnsWithIndent . 4 2}}{{end}}
+  {{.FullCommand|StyleCommand}}{{template "FormatCommand" .}}
+{{.Help|Wrap 4}}{{with DeprecationNotice .}}{{.|Wrap 4}}{{end}}
+{{with .Flags|AllFlagsToTwoColumns}}{{FormatTwoColumnsWithIndent . 4 2}}{{end}}{{with .Flags|FlagsLongHelp 4}}
+{{.}}{{end}}
 {{end -}}
 {{end -}}
 {{end -}}
@@ -217,18 +389,45 @@ var LongHelpTemplate = `{{define "FormatCommand" -}}
 
 {{end -}}
 
-usage: {{.App.Name}}{{template "FormatUsage" .App}}
-{{if .Context.Flags -}}
-Flags:
-{{.Context.Flags|FlagsToTwoColumns|FormatTwoColumns}}
-{{end -}}
+{{T "usage"|printf "%s:"|StyleHeading}} {{.App.Name}}{{template "FormatUsage" .App}}
+{{range .Context.Flags|AllFlagCategories -}}
+{{or .Name "Flags"|T|printf "%s:"|StyleHeading}}
+{{.Flags|AllFlagsToTwoColumns|FormatTwoColumns}}
+{{.Flags|FlagsLongHelp 2}}
+{{- end -}}
 {{if .Context.Args -}}
-Args:
+{{T "Args"|printf "%s:"|StyleHeading}}
 {{.Context.Args|ArgsToTwoColumns|FormatTwoColumns}}
-{{end -}}
+{{.Context.Args|ArgsLongHelp 2}}
+{{- end -}}
 {{if .App.Commands -}}
-Commands:
-{{template "FormatCommands" .App}}
+{{range .App.CmdGroupModel|CommandCategories -}}
+{{if .FlattenedCommands -}}
+{{or .Name "Commands"|T|printf "%s:"|StyleHeading}}
+{{template "FormatCommands" .}}
+{{end -}}
+{{end -}}
+{{end -}}
+{{template "FormatUserAliases" .App.UserAliases -}}
+{{with .App.AllExamples -}}
+{{T "Examples"|printf "%s:"|StyleHeading}}
+{{template "FormatExamples" .}}
+{{end -}}
+`
+
+// FlagHelpTemplate is the template used to describe a single flag, displayed
+// by --help=<flag> or "help <command> --<flag>".
+var FlagHelpTemplate = usageDefinitions + `{{with .Flag -}}
+{{FormatFlag false .|StyleFlag}}
+{{with .Help}}
+{{.|Wrap 2}}{{end}}
+{{with .HelpLong}}{{.|Wrap 2}}
+{{end -}}
+{{.|FlagDetails|FormatTwoColumns}}
+{{end -}}
+{{with .Examples -}}
+{{T "Examples"|printf "%s:"|StyleHeading}}
+{{template "FormatExamples" . -}}
 {{end -}}
 `
 
@@ -262,3 +461,160 @@ if [[ "$(basename -- ${(%):-%x})" != "_{{.App.Name}}" ]]; then
     compdef _{{.App.Name}} {{.App.Name}}
 fi
 `
+
+// DefaultHTMLDocsTemplate is the default html/template used by WriteHTMLDocs.
+var DefaultHTMLDocsTemplate = `{{define "header" -}}
+<!DOCTYPE html>
+<html lang="{{T "en"}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; display: flex; margin: 0; }
+nav { min-width: 14em; padding: 1em; background: #f4f4f4; }
+nav ul { list-style: none; padding: 0; }
+main { padding: 1em 2em; }
+td { padding: 0.2em 1em 0.2em 0; vertical-align: top; }
+.long { white-space: pre-line; }
+.deprecated { color: #a00; }
+</style>
+</head>
+<body>
+<nav>
+<a href="index.html"><strong>{{.App.Name}}</strong></a>
+<p><input id="search" type="search" placeholder="{{T "Search"}}"></p>
+<ul id="results"></ul>
+<ul>
+{{range .Commands -}}
+<li><a href="{{PageURL .}}">{{.FullCommand}}</a></li>
+{{end -}}
+</ul>
+</nav>
+<main>
+{{end -}}
+
+{{define "footer" -}}
+</main>
+<script>
+var search = document.getElementById("search"), results = document.getElementById("results"), entries = [];
+fetch("search.json").then(function(r) { return r.json(); }).then(function(e) { entries = e; });
+search.addEventListener("input", function() {
+  var term = search.value.toLowerCase();
+  results.innerHTML = "";
+  entries.filter(function(e) {
+    return term && (e.title + " " + e.text).toLowerCase().indexOf(term) >= 0;
+  }).forEach(function(e) {
+    var a = document.createElement("a"), li = document.createElement("li");
+    a.href = e.url;
+    a.textContent = e.title;
+    li.appendChild(a);
+    results.appendChild(li);
+  });
+});
+</script>
+</body>
+</html>
+{{end -}}
+
+{{define "flags" -}}
+<table>
+{{range . -}}
+{{if not .Hidden -}}
+<tr id="{{FlagAnchor .}}">
+<td><code>{{FormatFlag .}}</code></td>
+<td>{{AnnotatedHelp .}}{{with DeprecationNotice .}} <span class="deprecated">{{.}}</span>{{end}}{{with .HelpLong}}<div class="long">{{.}}</div>{{end}}</td>
+</tr>
+{{end -}}
+{{end -}}
+</table>
+{{end -}}
+
+{{define "args" -}}
+<table>
+{{range . -}}
+{{if not .Hidden -}}
+<tr>
+<td><code>{{FormatArg .}}</code></td>
+<td>{{AnnotatedHelp .}}{{with DeprecationNotice .}} <span class="deprecated">{{.}}</span>{{end}}{{with .HelpLong}}<div class="long">{{.}}</div>{{end}}</td>
+</tr>
+{{end -}}
+{{end -}}
+</table>
+{{end -}}
+
+{{define "examples" -}}
+{{with . -}}
+<h2>{{T "Examples"}}</h2>
+<dl>
+{{range . -}}
+<dt><code>{{.Command}}</code></dt>
+<dd>{{.Help}}</dd>
+{{end -}}
+</dl>
+{{end -}}
+{{end -}}
+
+{{define "index" -}}
+{{template "header" . -}}
+<h1>{{.App.Name}}</h1>
+<pre>{{T "usage"|printf "%s:"}} {{FormatAppUsage .App}}{{if .App.Commands}} &lt;command&gt; [&lt;args&gt; ...]{{end}}</pre>
+<p class="long">{{.App.Help}}</p>
+{{with .App.Flags -}}
+<h2 id="flags">{{T "Flags"}}</h2>
+{{template "flags" . -}}
+{{end -}}
+{{with .App.Args -}}
+<h2>{{T "Args"}}</h2>
+{{template "args" . -}}
+{{end -}}
+{{with .Commands -}}
+<h2>{{T "Commands"}}</h2>
+<table>
+{{range . -}}
+<tr><td><a href="{{PageURL .}}"><code>{{.FullCommand}}</code></a></td><td>{{.Help}}</td></tr>
+{{end -}}
+</table>
+{{end -}}
+{{template "examples" .App.Examples -}}
+{{template "footer" . -}}
+{{end -}}
+
+{{define "command" -}}
+{{template "header" . -}}
+{{with .Command -}}
+<h1>{{$.App.Name}} {{.FullCommand}}</h1>
+<pre>{{T "usage"|printf "%s:"}} {{FormatCommandUsage $.App .}}</pre>
+<p>{{.Help}}</p>
+{{with .HelpLong}}<p class="long">{{.}}</p>
+{{end -}}
+{{with DeprecationNotice .}}<p class="deprecated">{{.}}</p>
+{{end -}}
+{{with .Flags -}}
+<h2 id="flags">{{T "Flags"}}</h2>
+{{template "flags" . -}}
+{{end -}}
+{{with .Args -}}
+<h2>{{T "Args"}}</h2>
+{{template "args" . -}}
+{{end -}}
+{{with .Commands -}}
+<h2>{{T "Subcommands"}}</h2>
+<table>
+{{range . -}}
+{{if not .Hidden -}}
+<tr><td><a href="{{PageURL .}}"><code>{{.FullCommand}}</code></a></td><td>{{.Help}}</td></tr>
+{{end -}}
+{{end -}}
+</table>
+{{end -}}
+<p><a href="index.html#flags">{{T "Global flags"}}</a></p>
+{{range $.Parents -}}
+{{if .Flags -}}
+<p><a href="{{PageURL .}}#flags">{{printf (T "Flags of %s") .FullCommand}}</a></p>
+{{end -}}
+{{end -}}
+{{template "examples" .Examples -}}
+{{end -}}
+{{template "footer" . -}}
+{{end -}}
+`
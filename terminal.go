@@ -0,0 +1,12 @@
+//go:build appengine || (!linux && !freebsd && !darwin && !dragonfly && !netbsd && !openbsd)
+// +build appengine !linux,!freebsd,!darwin,!dragonfly,!netbsd,!openbsd
+
+package kingpin
+
+func isTerminal(v interface{}) bool {
+	return false
+}
+
+func withoutEcho(v interface{}, fn func() error) error {
+	return fn()
+}
@@ -0,0 +1,11 @@
+//go:build darwin || freebsd || dragonfly || netbsd || openbsd
+// +build darwin freebsd dragonfly netbsd openbsd
+
+package kingpin
+
+import "syscall"
+
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
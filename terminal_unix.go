@@ -0,0 +1,61 @@
+//go:build (!appengine && linux) || freebsd || darwin || dragonfly || netbsd || openbsd
+// +build !appengine,linux freebsd darwin dragonfly netbsd openbsd
+
+package kingpin
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type fder interface {
+	Fd() uintptr
+}
+
+func getTermios(v interface{}) (*syscall.Termios, uintptr) {
+	f, ok := v.(fder)
+	if !ok {
+		return nil, 0
+	}
+	fd := f.Fd()
+	var termios syscall.Termios
+	if _, _, err := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(ioctlGetTermios),
+		uintptr(unsafe.Pointer(&termios)),
+		0, 0, 0,
+	); err != 0 {
+		return nil, 0
+	}
+	return &termios, fd
+}
+
+func setTermios(fd uintptr, termios *syscall.Termios) {
+	_, _, _ = syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(ioctlSetTermios),
+		uintptr(unsafe.Pointer(termios)),
+		0, 0, 0,
+	)
+}
+
+// Determines if the supplied reader or writer is connected to a terminal.
+func isTerminal(v interface{}) bool {
+	termios, _ := getTermios(v)
+	return termios != nil
+}
+
+// Disables the terminal echo (if v is a terminal) while running fn.
+func withoutEcho(v interface{}, fn func() error) error {
+	termios, fd := getTermios(v)
+	if termios == nil {
+		return fn()
+	}
+	noEcho := *termios
+	noEcho.Lflag &^= syscall.ECHO
+	setTermios(fd, &noEcho)
+	defer setTermios(fd, termios)
+	return fn()
+}
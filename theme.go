@@ -0,0 +1,94 @@
+package kingpin
+
+import (
+	"os"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Style is a sequence of ANSI SGR parameters, such as "1" (bold) or "1;32"
+// (bold green). An empty style leaves the text unchanged.
+type Style string
+
+// Render returns the text surrounded by the escape sequences of the style.
+func (s Style) Render(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(s) + "m" + text + "\x1b[0m"
+}
+
+// Theme defines the styles applied to the elements of the usage.
+type Theme struct {
+	Heading  Style // Section headings, such as "Flags:"
+	Command  Style // Command names and command lines
+	Flag     Style // Flag and argument names
+	Default  Style // Default values
+	Required Style // Names of the required flags and arguments
+}
+
+// DefaultTheme is a theme readable on both dark and light terminals.
+var DefaultTheme = &Theme{
+	Heading:  "1",
+	Command:  "1",
+	Flag:     "36",
+	Default:  "2",
+	Required: "1;33",
+}
+
+// Theme sets the styles of the usage. The styles are only applied when the
+// usage writer is a terminal and the NO_COLOR environment variable is not set.
+func (a *Application) Theme(theme *Theme) *Application {
+	a.theme = theme
+	return a
+}
+
+// Returns the theme to apply to the usage, nil if the usage must be plain.
+func (a *Application) activeTheme() *Theme {
+	if a.theme == nil || os.Getenv("NO_COLOR") != "" || !isUsageTerminal(a.displayWriter()) {
+		return nil
+	}
+	return a.theme
+}
+
+func (t *Theme) heading(text string) string {
+	if t == nil {
+		return text
+	}
+	return t.Heading.Render(text)
+}
+
+func (t *Theme) command(text string) string {
+	if t == nil {
+		return text
+	}
+	return t.Command.Render(text)
+}
+
+func (t *Theme) flag(text string, required bool) string {
+	if t == nil {
+		return text
+	}
+	if required {
+		return t.Required.Render(text)
+	}
+	return t.Flag.Render(text)
+}
+
+func (t *Theme) defaultValue(text string) string {
+	if t == nil {
+		return text
+	}
+	return t.Default.Render(text)
+}
+
+// Overridden by the tests.
+var isUsageTerminal = isTerminal
+
+var escapeSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Returns the number of characters displayed by a text, ignoring the escape
+// sequences.
+func visibleWidth(text string) int {
+	return utf8.RuneCountInString(escapeSequence.ReplaceAllString(text, ""))
+}
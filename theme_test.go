@@ -0,0 +1,61 @@
+package kingpin
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withUsageTerminal(t *testing.T, terminal bool) {
+	previous := isUsageTerminal
+	isUsageTerminal = func(interface{}) bool { return terminal }
+	t.Cleanup(func() { isUsageTerminal = previous })
+}
+
+func newThemeApp(w io.Writer) *Application {
+	app := newTestApp().Writer(w).Theme(DefaultTheme)
+	app.Flag("name", "Name.").Required().String()
+	app.Flag("port", "Port.").Default("8080").Int()
+	app.Command("status", "Show the status.")
+	return app
+}
+
+func TestThemeUsage(t *testing.T) {
+	withUsageTerminal(t, true)
+	t.Setenv("NO_COLOR", "")
+	var buf bytes.Buffer
+	app := newThemeApp(&buf)
+	app.Usage(nil)
+	usage := buf.String()
+	assert.Contains(t, usage, "\x1b[1musage:\x1b[0m test")
+	assert.Contains(t, usage, "\x1b[1mFlags:\x1b[0m\n")
+	assert.Contains(t, usage, "  \x1b[1;33m--name\x1b[0m=NAME  Name.\n")
+	assert.Contains(t, usage, "  \x1b[36m--port\x1b[0m=\x1b[2m8080\x1b[0m  Port.\n")
+	assert.Contains(t, usage, "\x1b[1mstatus\x1b[0m")
+
+	buf.Reset()
+	newThemeApp(&buf).Theme(nil).Usage(nil)
+	plain := buf.String()
+	assert.Equal(t, plain, escapeSequence.ReplaceAllString(usage, ""), "the layout does not depend on the styles")
+}
+
+func TestThemeDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	withUsageTerminal(t, false)
+	newThemeApp(&buf).Usage(nil)
+	assert.NotContains(t, buf.String(), "\x1b[")
+
+	withUsageTerminal(t, true)
+	t.Setenv("NO_COLOR", "1")
+	buf.Reset()
+	newThemeApp(&buf).Usage(nil)
+	assert.NotContains(t, buf.String(), "\x1b[")
+}
+
+func TestVisibleWidth(t *testing.T) {
+	assert.Equal(t, 6, visibleWidth("--port"))
+	assert.Equal(t, 6, visibleWidth(DefaultTheme.Flag.Render("--port")))
+	assert.Equal(t, 3, visibleWidth("été"))
+}
@@ -17,7 +17,7 @@ func formatTwoColumns(w io.Writer, indent, padding, width int, rows [][2]string)
 	// Find size of first column.
 	s := 0
 	for _, row := range rows {
-		if c := len(row[0]); c > s && c < 30 {
+		if c := visibleWidth(row[0]); c > s && c < 30 {
 			s = c
 		}
 	}
@@ -29,8 +29,13 @@ func formatTwoColumns(w io.Writer, indent, padding, width int, rows [][2]string)
 		buf := bytes.NewBuffer(nil)
 		doc.ToText(buf, row[1], "", preIndent, width-s-padding-indent)
 		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
-		fmt.Fprintf(w, "%s%-*s%*s", indentStr, s, row[0], padding, "")
-		if len(row[0]) >= 30 {
+		c := visibleWidth(row[0])
+		fill := padding
+		if c < s {
+			fill += s - c
+		}
+		fmt.Fprintf(w, "%s%s%*s", indentStr, row[0], fill, "")
+		if c >= 30 {
 			fmt.Fprintf(w, "\n%s%s", indentStr, offsetStr)
 		}
 		fmt.Fprintf(w, "%s\n", lines[0])
@@ -73,22 +78,29 @@ func formatCmdUsage(app *ApplicationModel, cmd *CmdModel) string {
 }
 
 func formatFlag(haveShort bool, flag *FlagModel) string {
+	return formatStyledFlag(haveShort, flag, nil)
+}
+
+func formatStyledFlag(haveShort bool, flag *FlagModel, theme *Theme) string {
 	flagString := ""
 	flagName := flag.Name
 	if flag.IsBoolFlag() {
 		flagName = "[no-]" + flagName
 	}
 	if flag.Short != 0 {
-		flagString += fmt.Sprintf("-%c, --%s", flag.Short, flagName)
+		flagString += theme.flag(fmt.Sprintf("-%c, --%s", flag.Short, flagName), flag.Required)
 	} else {
 		if haveShort {
-			flagString += fmt.Sprintf("    --%s", flagName)
-		} else {
-			flagString += fmt.Sprintf("--%s", flagName)
+			flagString += "    "
 		}
+		flagString += theme.flag("--"+flagName, flag.Required)
 	}
 	if !flag.IsBoolFlag() {
-		flagString += fmt.Sprintf("=%s", flag.FormatPlaceHolder())
+		placeHolder := flag.FormatPlaceHolder()
+		if flag.PlaceHolder == "" && len(flag.Default) > 0 && !flag.Secret {
+			placeHolder = theme.defaultValue(placeHolder)
+		}
+		flagString += "=" + placeHolder
 	}
 	if v, ok := flag.Value.(repeatableFlag); ok && v.IsCumulative() {
 		flagString += " ..."
@@ -96,6 +108,71 @@ func formatFlag(haveShort bool, flag *FlagModel) string {
 	return flagString
 }
 
+// Returns the rows describing the visible flags, translated with the catalog.
+// The deprecated flags are only included if withDeprecated is set.
+func flagsToTwoColumns(f []*FlagModel, withDeprecated bool, theme *Theme, annotations Annotations, catalog MessageCatalog) [][2]string {
+	rows := [][2]string{}
+	haveShort := false
+	for _, flag := range f {
+		if flag.Short != 0 {
+			haveShort = true
+			break
+		}
+	}
+	for _, flag := range f {
+		if !flag.Hidden && (withDeprecated || !flag.Deprecated) {
+			rows = append(rows, [2]string{formatStyledFlag(haveShort, flag, theme), withNotice(flag.annotatedHelp(annotations, catalog), deprecationNotice(catalog, flag.Deprecated, flag.DeprecationNote))})
+		}
+	}
+	return rows
+}
+
+func argsToTwoColumns(args []*ArgModel, theme *Theme, annotations Annotations, catalog MessageCatalog) [][2]string {
+	rows := [][2]string{}
+	for _, arg := range args {
+		if !arg.Hidden {
+			rows = append(rows, [2]string{theme.flag(formatArg(arg), arg.Required), withNotice(arg.annotatedHelp(annotations, catalog), deprecationNotice(catalog, arg.Deprecated, arg.DeprecationNote))})
+		}
+	}
+	return rows
+}
+
+func formatArg(arg *ArgModel) string {
+	s := "<" + arg.Name + ">"
+	if arg.PlaceHolder != "" {
+		s = arg.PlaceHolder
+	}
+	if !arg.Required {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// Formats the long help texts of the rows (name, long help), each one below
+// its name.
+func formatLongHelp(w io.Writer, indent, width int, rows [][2]string) {
+	indentStr := strings.Repeat(" ", indent)
+	helpIndent := strings.Repeat(" ", indent+4)
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		fmt.Fprintf(w, "%s%s\n", indentStr, row[0])
+		doc.ToText(w, row[1], helpIndent, "  "+helpIndent, width-indent-4)
+		fmt.Fprintln(w)
+	}
+}
+
+func withNotice(help, notice string) string {
+	if notice == "" {
+		return help
+	}
+	if help == "" {
+		return notice
+	}
+	return help + " " + notice
+}
+
 type templateParseContext struct {
 	SelectedCommand *CmdModel
 	*FlagGroupModel
@@ -103,9 +180,10 @@ type templateParseContext struct {
 }
 
 type templateContext struct {
-	App     *ApplicationModel
-	Width   int
-	Context *templateParseContext
+	App      *ApplicationModel
+	Width    int
+	Context  *templateParseContext
+	ManPages bool // A man page is written for each command (see WriteManPages)
 }
 
 // UsageForContext displays usage information from a ParseContext (obtained from
@@ -116,7 +194,37 @@ func (a *Application) UsageForContext(context *ParseContext) error {
 
 // UsageForContextWithTemplate is the base usage function. You generally don't need to use this.
 func (a *Application) UsageForContextWithTemplate(context *ParseContext, indent int, tmpl string) error {
-	width := guessWidth(a.usageWriter)
+	var selectedCommand *CmdModel
+	if context.SelectedCommand != nil {
+		selectedCommand = context.SelectedCommand.Model()
+	}
+	flags := context.flags.Model()
+	a.translateBuiltinHelp(flags.Flags...)
+	ctx := templateContext{
+		App:   a.Model(),
+		Width: guessWidth(a.displayWriter()),
+		Context: &templateParseContext{
+			SelectedCommand: selectedCommand,
+			FlagGroupModel:  flags,
+			ArgGroupModel:   context.arguments.Model(),
+		},
+	}
+	return a.executeTemplate(a.usageWriter, indent, tmpl, ctx)
+}
+
+// Renders a template to w with the usage functions.
+func (a *Application) executeTemplate(w io.Writer, indent int, tmpl string, data interface{}) error {
+	t, err := a.parseTemplate(indent, tmpl)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
+
+// Parses a template with the usage functions.
+func (a *Application) parseTemplate(indent int, tmpl string) (*template.Template, error) {
+	width := guessWidth(a.displayWriter())
+	theme := a.activeTheme()
 	funcs := template.FuncMap{
 		"Indent": func(level int) string {
 			return strings.Repeat(" ", level*indent)
@@ -129,20 +237,10 @@ func (a *Application) UsageForContextWithTemplate(context *ParseContext, indent
 		},
 		"FormatFlag": formatFlag,
 		"FlagsToTwoColumns": func(f []*FlagModel) [][2]string {
-			rows := [][2]string{}
-			haveShort := false
-			for _, flag := range f {
-				if flag.Short != 0 {
-					haveShort = true
-					break
-				}
-			}
-			for _, flag := range f {
-				if !flag.Hidden {
-					rows = append(rows, [2]string{formatFlag(haveShort, flag), flag.HelpWithEnvar()})
-				}
-			}
-			return rows
+			return flagsToTwoColumns(f, false, theme, a.annotations, a.messages)
+		},
+		"AllFlagsToTwoColumns": func(f []*FlagModel) [][2]string {
+			return flagsToTwoColumns(f, true, theme, a.annotations, a.messages)
 		},
 		"RequiredFlags": func(f []*FlagModel) []*FlagModel {
 			requiredFlags := []*FlagModel{}
@@ -162,22 +260,65 @@ func (a *Application) UsageForContextWithTemplate(context *ParseContext, indent
 			}
 			return optionalFlags
 		},
-		"ArgsToTwoColumns": func(a []*ArgModel) [][2]string {
-			rows := [][2]string{}
-			for _, arg := range a {
+		"ArgsToTwoColumns": func(args []*ArgModel) [][2]string {
+			return argsToTwoColumns(args, theme, a.annotations, a.messages)
+		},
+		"Annotations": parseAnnotations,
+		"AnnotatedFlagsToTwoColumns": func(annotations Annotations, f []*FlagModel) [][2]string {
+			return flagsToTwoColumns(f, false, theme, annotations, a.messages)
+		},
+		"AnnotatedArgsToTwoColumns": func(annotations Annotations, args []*ArgModel) [][2]string {
+			return argsToTwoColumns(args, theme, annotations, a.messages)
+		},
+		"FlagDetails": func(flag *FlagModel) [][2]string {
+			return flagDetails(flag, a.messages)
+		},
+		"T": a.translate,
+		"DeprecationNotice": func(model interface{}) string {
+			return modelDeprecationNotice(model, a.messages)
+		},
+		"FlagsLongHelp": func(indent int, f []*FlagModel) string {
+			var rows [][2]string
+			for _, flag := range f {
+				if !flag.Hidden {
+					rows = append(rows, [2]string{theme.flag(formatFlag(false, flag), flag.Required), flag.HelpLong})
+				}
+			}
+			buf := bytes.NewBuffer(nil)
+			formatLongHelp(buf, indent, width, rows)
+			return buf.String()
+		},
+		"ArgsLongHelp": func(indent int, args []*ArgModel) string {
+			var rows [][2]string
+			for _, arg := range args {
 				if !arg.Hidden {
-					var s string
-					if arg.PlaceHolder != "" {
-						s = arg.PlaceHolder
-					} else {
-						s = "<" + arg.Name + ">"
-					}
-					if !arg.Required {
-						s = "[" + s + "]"
-					}
-					rows = append(rows, [2]string{s, arg.HelpWithEnvar()})
+					rows = append(rows, [2]string{theme.flag(formatArg(arg), arg.Required), arg.HelpLong})
 				}
 			}
+			buf := bytes.NewBuffer(nil)
+			formatLongHelp(buf, indent, width, rows)
+			return buf.String()
+		},
+		"Roff": roffEscape,
+		"ManPageName": func(cmd *CmdModel) string {
+			return manPageName(a.Name, cmd)
+		},
+		"ManPageCommands":  manPageCommands,
+		"EnvironmentFlags": environmentFlags,
+		"FlagCategories": func(f []*FlagModel) []*FlagCategoryModel {
+			return flagCategories(a.categories, f, false)
+		},
+		"AllFlagCategories": func(f []*FlagModel) []*FlagCategoryModel {
+			return flagCategories(a.categories, f, true)
+		},
+		"CommandCategories": func(c *CmdGroupModel) []*CmdCategoryModel {
+			return cmdCategories(a.categories, c)
+		},
+		"UserAliasesToTwoColumns": func(aliases []*UserAliasModel) [][2]string {
+			rows := [][2]string{}
+			for _, alias := range aliases {
+				rows = append(rows, [2]string{alias.Name, alias.String()})
+			}
 			return rows
 		},
 		"FormatTwoColumns": func(rows [][2]string) string {
@@ -199,27 +340,19 @@ func (a *Application) UsageForContextWithTemplate(context *ParseContext, indent
 		"Char": func(c rune) string {
 			return string(c)
 		},
+		"StyleHeading": theme.heading,
+		"StyleCommand": theme.command,
+		"StyleFlag": func(text string) string {
+			return theme.flag(text, false)
+		},
+		"StyleRequired": func(text string) string {
+			return theme.flag(text, true)
+		},
+		"StyleDefault": theme.defaultValue,
 	}
 	for k, v := range a.usageFuncs {
 		funcs[k] = v
 	}
 
-	t, err := template.New("usage").Funcs(funcs).Parse(tmpl)
-	if err != nil {
-		return err
-	}
-	var selectedCommand *CmdModel
-	if context.SelectedCommand != nil {
-		selectedCommand = context.SelectedCommand.Model()
-	}
-	ctx := templateContext{
-		App:   a.Model(),
-		Width: width,
-		Context: &templateParseContext{
-			SelectedCommand: selectedCommand,
-			FlagGroupModel:  context.flags.Model(),
-			ArgGroupModel:   context.arguments.Model(),
-		},
-	}
-	return t.Execute(a.usageWriter, ctx)
+	return template.New("usage").Funcs(funcs).Parse(tmpl)
 }
@@ -0,0 +1,135 @@
+package kingpin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// UserAliases registers user-defined command aliases, generally loaded from a
+// configuration file. When the first command token of the command line matches
+// one of the aliases, it is replaced by the associated arguments before the
+// command line is tokenized.
+//
+// For example, {"dp": {"deploy", "--env", "prod", "--wait"}} makes "app dp"
+// equivalent to "app deploy --env prod --wait".
+//
+// Aliases can refer to other aliases. If nil is supplied, the previously
+// defined aliases are cleared.
+func (a *Application) UserAliases(aliases map[string][]string) *Application {
+	if aliases == nil {
+		a.userAliases = nil
+		return a
+	}
+	if a.userAliases == nil {
+		a.userAliases = make(map[string][]string, len(aliases))
+	}
+	for name, expansion := range aliases {
+		a.userAliases[name] = expansion
+	}
+	return a
+}
+
+// userAliasNames returns the sorted list of user aliases.
+func (a *Application) userAliasNames() []string {
+	names := make([]string, 0, len(a.userAliases))
+	for name := range a.userAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ensure that user aliases are valid and do not hide an existing command.
+// Must be called once the commands have been initialized.
+func (a *Application) checkUserAliases() error {
+	for _, name := range a.userAliasNames() {
+		if name == "" || strings.HasPrefix(name, "-") {
+			return fmt.Errorf("invalid user alias %q", name)
+		}
+		if len(a.userAliases[name]) == 0 {
+			return fmt.Errorf("user alias %q has no expansion", name)
+		}
+		if _, exist := a.commands[name]; exist {
+			return fmt.Errorf("user alias %q conflicts with existing command", name)
+		}
+	}
+	return nil
+}
+
+// Replace the first command token by its user alias expansion (if any).
+func (a *Application) expandUserAliases(args []string) ([]string, error) {
+	if len(a.userAliases) == 0 {
+		return args, nil
+	}
+
+	var chain []string
+	for i := a.firstCommandToken(args); i < len(args); i = a.firstCommandToken(args) {
+		expansion, ok := a.userAliases[args[i]]
+		if !ok {
+			break
+		}
+		for _, previous := range chain {
+			if previous == args[i] {
+				return nil, fmt.Errorf("recursive user alias %s", strings.Join(append(chain, args[i]), " -> "))
+			}
+		}
+		chain = append(chain, args[i])
+
+		expanded := make([]string, 0, len(args)+len(expansion)-1)
+		expanded = append(expanded, args[:i]...)
+		expanded = append(expanded, expansion...)
+		args = append(expanded, args[i+1:]...)
+	}
+	return args, nil
+}
+
+// Returns the index of the first argument that is not a top level flag or a
+// top level flag value (or len(args) if there is none).
+func (a *Application) firstCommandToken(args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return len(args)
+		case strings.HasPrefix(arg, "--"):
+			if strings.Contains(arg, "=") {
+				continue
+			}
+			flag, _, _ := a.flagGroup.getFlagAlias(arg[2:])
+			if flag != nil && !isBoolValue(flag.value) {
+				// The next argument is the flag value
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if a.shortFlagsTakeNextArg(arg[1:]) {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return len(args)
+}
+
+// Returns true if the last flag of combined short flags such as -vo takes the
+// next argument as value. The letters following a short flag taking a value
+// are the value itself, such as in -ojson.
+func (a *Application) shortFlagsTakeNextArg(flags string) bool {
+	for i, short := range flags {
+		flag := a.flagGroup.short[string(short)]
+		if flag == nil {
+			return false
+		}
+		if !isBoolValue(flag.value) {
+			return i+utf8.RuneLen(short) == len(flags)
+		}
+	}
+	return false
+}
+
+func isBoolValue(value Value) bool {
+	fb, ok := value.(boolFlag)
+	return ok && fb.IsBoolFlag()
+}
@@ -0,0 +1,121 @@
+package kingpin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newUserAliasesApp() (app *Application, env *string, wait *bool) {
+	app = newTestApp()
+	app.Flag("output", "").Short('o').String()
+	app.Flag("debug", "").Bool()
+	app.Flag("verbose", "").Short('v').Bool()
+	deploy := app.Command("deploy", "Deploy the application.")
+	env = deploy.Flag("env", "").String()
+	wait = deploy.Flag("wait", "").Bool()
+	app.Command("status", "Show status.").Alias("st")
+	return
+}
+
+func TestUserAliases(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		command string
+		env     string
+		wait    bool
+	}{
+		{"Alias", []string{"dp"}, "deploy", "", true},
+		{"Alias with extra args", []string{"dp", "--env", "dev"}, "deploy", "dev", true},
+		{"Nested alias", []string{"dps"}, "deploy", "staging", true},
+		{"After top level flags", []string{"--debug", "-o", "dp", "dps"}, "deploy", "staging", true},
+		{"After top level flag with value", []string{"--output=json", "dp"}, "deploy", "", true},
+		{"After combined short flags", []string{"-vo", "dp", "dps"}, "deploy", "staging", true},
+		{"After short flag with inline value", []string{"-vodp", "dps"}, "deploy", "staging", true},
+		{"After short bool flags", []string{"-v", "dps"}, "deploy", "staging", true},
+		{"Not an alias", []string{"deploy", "dp"}, "", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			app, env, wait := newUserAliasesApp()
+			app.UserAliases(map[string][]string{
+				"dp":  {"deploy", "--wait"},
+				"dps": {"dp", "--env", "staging"},
+			})
+			command, err := app.Parse(c.args)
+			if c.command == "" {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.command, command)
+			assert.Equal(t, c.env, *env)
+			assert.Equal(t, c.wait, *wait)
+		})
+	}
+}
+
+func TestUserAliasesRecursive(t *testing.T) {
+	app, _, _ := newUserAliasesApp()
+	app.UserAliases(map[string][]string{
+		"a": {"b", "--wait"},
+		"b": {"a"},
+	})
+	_, err := app.Parse([]string{"a"})
+	assert.EqualError(t, err, "recursive user alias a -> b -> a")
+}
+
+func TestUserAliasesConflicts(t *testing.T) {
+	for _, name := range []string{"deploy", "st", "help"} {
+		app, _, _ := newUserAliasesApp()
+		app.UserAliases(map[string][]string{name: {"deploy"}})
+		_, err := app.Parse([]string{"deploy"})
+		assert.EqualError(t, err, `user alias "`+name+`" conflicts with existing command`)
+	}
+
+	app, _, _ := newUserAliasesApp()
+	app.UserAliases(map[string][]string{"empty": nil})
+	_, err := app.Parse([]string{"deploy"})
+	assert.EqualError(t, err, `user alias "empty" has no expansion`)
+}
+
+func TestUserAliasesCleared(t *testing.T) {
+	app, _, _ := newUserAliasesApp()
+	app.UserAliases(map[string][]string{"dp": {"deploy"}})
+	app.UserAliases(nil)
+	_, err := app.Parse([]string{"dp"})
+	assert.Error(t, err)
+}
+
+func TestUserAliasesCompletion(t *testing.T) {
+	app, _, _ := newUserAliasesApp()
+	app.UserAliases(map[string][]string{"dp": {"deploy"}, "ci": {"status"}})
+	context, err := app.ParseContext([]string{"--completion-bash"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"help", "deploy", "status", "ci", "dp"}, app.completionOptions(context))
+
+	context, err = app.ParseContext([]string{"--completion-bash", "dp", "--"})
+	assert.NoError(t, err)
+	assert.Contains(t, app.completionOptions(context), "--env")
+}
+
+func TestUserAliasesUsage(t *testing.T) {
+	var buf bytes.Buffer
+	app, _, _ := newUserAliasesApp()
+	app.Writer(&buf)
+	app.UserAliases(map[string][]string{"dp": {"deploy", "--env", "prod"}})
+	app.Usage(nil)
+	assert.Contains(t, buf.String(), "Aliases:\n  dp  deploy --env prod\n")
+
+	buf.Reset()
+	app.Usage([]string{"deploy"})
+	assert.NotContains(t, buf.String(), "Aliases:")
+
+	for _, template := range []string{CompactUsageTemplate, SeparateOptionalFlagsUsageTemplate, LongHelpTemplate} {
+		buf.Reset()
+		app.UsageTemplate(template).Usage(nil)
+		assert.Contains(t, buf.String(), "Aliases:\n  dp  deploy --env prod\n")
+	}
+}
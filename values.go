@@ -41,6 +41,46 @@ type Getter interface {
 	Get() interface{}
 }
 
+// Resettable is an optional interface for values that can restore their
+// target to its zero value (an empty slice or map for cumulative values). All
+// Value types provided by this package satisfy the Resettable interface.
+//
+// It is used by Application.Reset() to reuse an application for several
+// parses, which then restores the initial value of the targets of the values
+// provided by this package.
+type Resettable interface {
+	Reset()
+}
+
+// Implemented by the values bound to a variable, returning a pointer to it.
+type boundValue interface {
+	target() interface{}
+}
+
+// Returns a function restoring the variable pointed by target to its current
+// value.
+func snapshotTarget(target interface{}) func() {
+	v := reflect.ValueOf(target).Elem()
+	initial := copyTarget(v)
+	return func() { v.Set(copyTarget(initial)) }
+}
+
+// Returns a copy of a variable. The maps are copied since their values are set
+// in place, unlike the other types whose values are replaced (the slices are
+// only appended to).
+func copyTarget(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Map && !v.IsNil() {
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return c
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
 // Optional interface to indicate boolean flags that don't accept a value, and
 // implicitly have a --no-<x> negation counterpart.
 type boolFlag interface {
@@ -58,6 +98,18 @@ type repeatableFlag interface {
 	IsCumulative() bool
 }
 
+// Enumerable is implemented by the values restricted to a set of options,
+// such as the values created by Enum() and Enums().
+type Enumerable interface {
+	Options() []string
+}
+
+// Optional interface for values able to create a copy of themselves that is
+// not bound to any target.
+type cloneable interface {
+	clone() Value
+}
+
 // Text is the interface to the dynamic value stored in a flag.
 // (The default value is represented as a string.)
 type Text interface {
@@ -78,6 +130,20 @@ func (w *wrapText) Set(s string) error {
 	return w.text.UnmarshalText([]byte(s))
 }
 
+func (w *wrapText) clone() Value {
+	typ := reflect.TypeOf(w.text)
+	if typ.Kind() != reflect.Ptr {
+		return nil
+	}
+	return &wrapText{reflect.New(typ.Elem()).Interface().(Text)}
+}
+
+func (w *wrapText) Reset() {
+	if v := reflect.ValueOf(w.text); v.Kind() == reflect.Ptr && !v.IsNil() {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+}
+
 type accumulator struct {
 	element func(value interface{}) Value
 	typ     reflect.Type
@@ -130,6 +196,16 @@ func (a *accumulator) IsCumulative() bool {
 	return true
 }
 
+func (a *accumulator) clone() Value {
+	return newAccumulator(reflect.New(a.slice.Type().Elem()).Interface(), a.element)
+}
+
+func (a *accumulator) target() interface{} { return a.slice.Interface() }
+
+func (a *accumulator) Reset() {
+	a.slice.Elem().Set(reflect.Zero(a.slice.Elem().Type()))
+}
+
 func (b *boolValue) IsBoolFlag() bool { return true }
 
 // -- time.Duration Value
@@ -149,6 +225,10 @@ func (d *durationValue) Get() interface{} { return time.Duration(*d) }
 
 func (d *durationValue) String() string { return (*time.Duration)(d).String() }
 
+func (d *durationValue) clone() Value        { return newDurationValue(new(time.Duration)) }
+func (d *durationValue) Reset()              { *d = 0 }
+func (d *durationValue) target() interface{} { return (*time.Duration)(d) }
+
 // -- map[string]string Value
 type stringMapValue map[string]string
 
@@ -161,7 +241,7 @@ var stringMapRegex = regexp.MustCompile("[:=]")
 func (s *stringMapValue) Set(value string) error {
 	parts := stringMapRegex.Split(value, 2)
 	if len(parts) != 2 {
-		return fmt.Errorf("expected KEY=VALUE got '%s'", value)
+		return translatableErrorf("expected KEY=VALUE got '%s'", value)
 	}
 	(*s)[parts[0]] = parts[1]
 	return nil
@@ -179,6 +259,18 @@ func (s *stringMapValue) IsCumulative() bool {
 	return true
 }
 
+func (s *stringMapValue) clone() Value {
+	return newStringMapValue(&map[string]string{})
+}
+
+func (s *stringMapValue) target() interface{} { return (*map[string]string)(s) }
+
+func (s *stringMapValue) Reset() {
+	for key := range *s {
+		delete(*s, key)
+	}
+}
+
 // -- net.IP Value
 type ipValue net.IP
 
@@ -189,7 +281,7 @@ func newIPValue(p *net.IP) *ipValue {
 func (i *ipValue) Set(value string) error {
 	var ip net.IP
 	if ip = net.ParseIP(value); ip == nil {
-		return fmt.Errorf("'%s' is not an IP address", value)
+		return translatableErrorf("'%s' is not an IP address", value)
 	}
 	*i = *(*ipValue)(&ip)
 	return nil
@@ -203,6 +295,10 @@ func (i *ipValue) String() string {
 	return (*net.IP)(i).String()
 }
 
+func (i *ipValue) clone() Value        { return newIPValue(new(net.IP)) }
+func (i *ipValue) Reset()              { *i = nil }
+func (i *ipValue) target() interface{} { return (*net.IP)(i) }
+
 // -- *net.TCPAddr Value
 type tcpAddrValue struct {
 	addr **net.TCPAddr
@@ -215,7 +311,7 @@ func newTCPAddrValue(p **net.TCPAddr) *tcpAddrValue {
 func (i *tcpAddrValue) Set(value string) error {
 	addr, err := net.ResolveTCPAddr("tcp", value)
 	if err != nil {
-		return fmt.Errorf("'%s' is not a valid TCP address: %s", value, err)
+		return translatableErrorf("'%s' is not a valid TCP address: %s", value, err)
 	}
 	*i.addr = addr
 	return nil
@@ -229,6 +325,10 @@ func (i *tcpAddrValue) String() string {
 	return (*i.addr).String()
 }
 
+func (i *tcpAddrValue) clone() Value        { return newTCPAddrValue(new(*net.TCPAddr)) }
+func (i *tcpAddrValue) Reset()              { *i.addr = nil }
+func (i *tcpAddrValue) target() interface{} { return i.addr }
+
 // -- existingFile Value
 
 type fileStatValue struct {
@@ -245,7 +345,7 @@ func newFileStatValue(p *string, predicate func(os.FileInfo) error) *fileStatVal
 
 func (e *fileStatValue) Set(value string) error {
 	if s, err := os.Stat(value); os.IsNotExist(err) {
-		return fmt.Errorf("path '%s' does not exist", value)
+		return translatableErrorf("path '%s' does not exist", value)
 	} else if err != nil {
 		return err
 	} else if err := e.predicate(s); err != nil {
@@ -263,27 +363,63 @@ func (e *fileStatValue) String() string {
 	return *e.path
 }
 
+func (e *fileStatValue) clone() Value        { return newFileStatValue(new(string), e.predicate) }
+func (e *fileStatValue) Reset()              { *e.path = "" }
+func (e *fileStatValue) target() interface{} { return e.path }
+
 // -- os.File value
 
 type fileValue struct {
-	f    **os.File
-	flag int
-	perm os.FileMode
+	f      **os.File
+	flag   int
+	perm   os.FileMode
+	opened *os.File // File opened by Set, that must be closed
 }
 
 func newFileValue(p **os.File, flag int, perm os.FileMode) *fileValue {
-	return &fileValue{p, flag, perm}
+	return &fileValue{f: p, flag: flag, perm: perm}
 }
 
+// Opens the file, - meaning stdin (or stdout if the file is opened for
+// writing). The file previously opened by the value is closed.
 func (f *fileValue) Set(value string) error {
+	if value == "-" {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		if f.flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			*f.f = os.Stdout
+		} else {
+			*f.f = os.Stdin
+		}
+		return nil
+	}
 	fd, err := os.OpenFile(value, f.flag, f.perm)
 	if err != nil {
 		return err
 	}
-	*f.f = fd
+	if err = f.Close(); err != nil {
+		fd.Close()
+		return err
+	}
+	*f.f, f.opened = fd, fd
 	return nil
 }
 
+// Close closes the file opened by the value, if any (stdin and stdout are
+// never closed), and sets the target to nil.
+func (f *fileValue) Close() error {
+	if f.opened == nil {
+		return nil
+	}
+	fd := f.opened
+	f.opened = nil
+	if *f.f == fd {
+		*f.f = nil
+	}
+	return fd.Close()
+}
+
 func (f *fileValue) Get() interface{} {
 	return (*os.File)(*f.f)
 }
@@ -295,6 +431,29 @@ func (f *fileValue) String() string {
 	return (*f.f).Name()
 }
 
+func (f *fileValue) acceptsStdio() {}
+
+func (f *fileValue) target() interface{} { return f.f }
+
+// Takes over the file opened by the clone, which is no longer closed by the
+// clone.
+func (f *fileValue) adopt(clone Value) error {
+	c := clone.(*fileValue)
+	if err := f.Close(); err != nil {
+		return err
+	}
+	*f.f, f.opened = *c.f, c.opened
+	c.opened = nil
+	return nil
+}
+
+func (f *fileValue) clone() Value { return newFileValue(new(*os.File), f.flag, f.perm) }
+
+func (f *fileValue) Reset() {
+	f.Close()
+	*f.f = nil
+}
+
 // -- url.URL Value
 type urlValue struct {
 	u **url.URL
@@ -307,7 +466,7 @@ func newURLValue(p **url.URL) *urlValue {
 func (u *urlValue) Set(value string) error {
 	url, err := url.Parse(value)
 	if err != nil {
-		return fmt.Errorf("invalid URL: %s", err)
+		return translatableErrorf("invalid URL: %s", err)
 	}
 	*u.u = url
 	return nil
@@ -324,6 +483,10 @@ func (u *urlValue) String() string {
 	return (*u.u).String()
 }
 
+func (u *urlValue) clone() Value        { return newURLValue(new(*url.URL)) }
+func (u *urlValue) Reset()              { *u.u = nil }
+func (u *urlValue) target() interface{} { return u.u }
+
 // -- []*url.URL Value
 type urlListValue []*url.URL
 
@@ -334,7 +497,7 @@ func newURLListValue(p *[]*url.URL) *urlListValue {
 func (u *urlListValue) Set(value string) error {
 	url, err := url.Parse(value)
 	if err != nil {
-		return fmt.Errorf("invalid URL: %s", err)
+		return translatableErrorf("invalid URL: %s", err)
 	}
 	*u = append(*u, url)
 	return nil
@@ -356,6 +519,10 @@ func (u *urlListValue) IsCumulative() bool {
 	return true
 }
 
+func (u *urlListValue) clone() Value        { return newURLListValue(new([]*url.URL)) }
+func (u *urlListValue) Reset()              { *u = nil }
+func (u *urlListValue) target() interface{} { return (*[]*url.URL)(u) }
+
 // A flag whose value must be in a set of options.
 type enumValue struct {
 	value   *string
@@ -380,13 +547,21 @@ func (a *enumValue) Set(value string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("enum value must be one of %s, got '%s'", strings.Join(a.options, ","), value)
+	return translatableErrorf("enum value must be one of %s, got '%s'", strings.Join(a.options, ","), value)
 }
 
 func (a *enumValue) Get() interface{} {
 	return (string)(*a.value)
 }
 
+func (a *enumValue) Options() []string {
+	return a.options
+}
+
+func (a *enumValue) clone() Value        { return newEnumFlag(new(string), a.options...) }
+func (a *enumValue) Reset()              { *a.value = "" }
+func (a *enumValue) target() interface{} { return a.value }
+
 // -- []string Enum Value
 type enumsValue struct {
 	value   *[]string
@@ -407,7 +582,7 @@ func (s *enumsValue) Set(value string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("enum value must be one of %s, got '%s'", strings.Join(s.options, ","), value)
+	return translatableErrorf("enum value must be one of %s, got '%s'", strings.Join(s.options, ","), value)
 }
 
 func (s *enumsValue) Get() interface{} {
@@ -422,6 +597,14 @@ func (s *enumsValue) IsCumulative() bool {
 	return true
 }
 
+func (s *enumsValue) Options() []string {
+	return s.options
+}
+
+func (s *enumsValue) clone() Value        { return newEnumsFlag(new([]string), s.options...) }
+func (s *enumsValue) Reset()              { *s.value = nil }
+func (s *enumsValue) target() interface{} { return s.value }
+
 // -- units.Base2Bytes Value
 type bytesValue units.Base2Bytes
 
@@ -439,10 +622,14 @@ func (d *bytesValue) Get() interface{} { return units.Base2Bytes(*d) }
 
 func (d *bytesValue) String() string { return (*units.Base2Bytes)(d).String() }
 
+func (d *bytesValue) clone() Value        { return newBytesValue(new(units.Base2Bytes)) }
+func (d *bytesValue) Reset()              { *d = 0 }
+func (d *bytesValue) target() interface{} { return (*units.Base2Bytes)(d) }
+
 func newExistingFileValue(target *string) *fileStatValue {
 	return newFileStatValue(target, func(s os.FileInfo) error {
 		if s.IsDir() {
-			return fmt.Errorf("'%s' is a directory", s.Name())
+			return translatableErrorf("'%s' is a directory", s.Name())
 		}
 		return nil
 	})
@@ -451,7 +638,7 @@ func newExistingFileValue(target *string) *fileStatValue {
 func newExistingDirValue(target *string) *fileStatValue {
 	return newFileStatValue(target, func(s os.FileInfo) error {
 		if !s.IsDir() {
-			return fmt.Errorf("'%s' is a file", s.Name())
+			return translatableErrorf("'%s' is a file", s.Name())
 		}
 		return nil
 	})
@@ -472,10 +659,13 @@ func (c *counterValue) Set(s string) error {
 	return nil
 }
 
-func (c *counterValue) Get() interface{}   { return (int)(*c) }
-func (c *counterValue) IsBoolFlag() bool   { return true }
-func (c *counterValue) String() string     { return fmt.Sprintf("%d", *c) }
-func (c *counterValue) IsCumulative() bool { return true }
+func (c *counterValue) Get() interface{}    { return (int)(*c) }
+func (c *counterValue) IsBoolFlag() bool    { return true }
+func (c *counterValue) String() string      { return fmt.Sprintf("%d", *c) }
+func (c *counterValue) IsCumulative() bool  { return true }
+func (c *counterValue) clone() Value        { return newCounterValue(new(int)) }
+func (c *counterValue) Reset()              { *c = 0 }
+func (c *counterValue) target() interface{} { return (*int)(c) }
 
 func resolveHost(value string) (net.IP, error) {
 	ip := net.ParseIP(value)
@@ -30,6 +30,12 @@ func (f *boolValue) Get() interface{} { return (bool)(*f.v) }
 
 func (f *boolValue) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *boolValue) clone() Value { return newBoolValue(new(bool)) }
+
+func (f *boolValue) Reset() { *f.v = *new(bool) }
+
+func (f *boolValue) target() interface{} { return f.v }
+
 // Bool parses the next command-line value as bool.
 func (p *parserMixin) Bool() (target *bool) {
 	target = new(bool)
@@ -70,6 +76,12 @@ func (f *stringValue) Get() interface{} { return (string)(*f.v) }
 
 func (f *stringValue) String() string { return string(*f.v) }
 
+func (f *stringValue) clone() Value { return newStringValue(new(string)) }
+
+func (f *stringValue) Reset() { *f.v = *new(string) }
+
+func (f *stringValue) target() interface{} { return f.v }
+
 // String parses the next command-line value as string.
 func (p *parserMixin) String() (target *string) {
 	target = new(string)
@@ -113,6 +125,12 @@ func (f *uintValue) Get() interface{} { return (uint)(*f.v) }
 
 func (f *uintValue) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *uintValue) clone() Value { return newUintValue(new(uint)) }
+
+func (f *uintValue) Reset() { *f.v = *new(uint) }
+
+func (f *uintValue) target() interface{} { return f.v }
+
 // Uint parses the next command-line value as uint.
 func (p *parserMixin) Uint() (target *uint) {
 	target = new(uint)
@@ -156,6 +174,12 @@ func (f *uint8Value) Get() interface{} { return (uint8)(*f.v) }
 
 func (f *uint8Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *uint8Value) clone() Value { return newUint8Value(new(uint8)) }
+
+func (f *uint8Value) Reset() { *f.v = *new(uint8) }
+
+func (f *uint8Value) target() interface{} { return f.v }
+
 // Uint8 parses the next command-line value as uint8.
 func (p *parserMixin) Uint8() (target *uint8) {
 	target = new(uint8)
@@ -199,6 +223,12 @@ func (f *uint16Value) Get() interface{} { return (uint16)(*f.v) }
 
 func (f *uint16Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *uint16Value) clone() Value { return newUint16Value(new(uint16)) }
+
+func (f *uint16Value) Reset() { *f.v = *new(uint16) }
+
+func (f *uint16Value) target() interface{} { return f.v }
+
 // Uint16 parses the next command-line value as uint16.
 func (p *parserMixin) Uint16() (target *uint16) {
 	target = new(uint16)
@@ -242,6 +272,12 @@ func (f *uint32Value) Get() interface{} { return (uint32)(*f.v) }
 
 func (f *uint32Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *uint32Value) clone() Value { return newUint32Value(new(uint32)) }
+
+func (f *uint32Value) Reset() { *f.v = *new(uint32) }
+
+func (f *uint32Value) target() interface{} { return f.v }
+
 // Uint32 parses the next command-line value as uint32.
 func (p *parserMixin) Uint32() (target *uint32) {
 	target = new(uint32)
@@ -285,6 +321,12 @@ func (f *uint64Value) Get() interface{} { return (uint64)(*f.v) }
 
 func (f *uint64Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *uint64Value) clone() Value { return newUint64Value(new(uint64)) }
+
+func (f *uint64Value) Reset() { *f.v = *new(uint64) }
+
+func (f *uint64Value) target() interface{} { return f.v }
+
 // Uint64 parses the next command-line value as uint64.
 func (p *parserMixin) Uint64() (target *uint64) {
 	target = new(uint64)
@@ -328,6 +370,12 @@ func (f *intValue) Get() interface{} { return (int)(*f.v) }
 
 func (f *intValue) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *intValue) clone() Value { return newIntValue(new(int)) }
+
+func (f *intValue) Reset() { *f.v = *new(int) }
+
+func (f *intValue) target() interface{} { return f.v }
+
 // Int parses the next command-line value as int.
 func (p *parserMixin) Int() (target *int) {
 	target = new(int)
@@ -371,6 +419,12 @@ func (f *int8Value) Get() interface{} { return (int8)(*f.v) }
 
 func (f *int8Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *int8Value) clone() Value { return newInt8Value(new(int8)) }
+
+func (f *int8Value) Reset() { *f.v = *new(int8) }
+
+func (f *int8Value) target() interface{} { return f.v }
+
 // Int8 parses the next command-line value as int8.
 func (p *parserMixin) Int8() (target *int8) {
 	target = new(int8)
@@ -414,6 +468,12 @@ func (f *int16Value) Get() interface{} { return (int16)(*f.v) }
 
 func (f *int16Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *int16Value) clone() Value { return newInt16Value(new(int16)) }
+
+func (f *int16Value) Reset() { *f.v = *new(int16) }
+
+func (f *int16Value) target() interface{} { return f.v }
+
 // Int16 parses the next command-line value as int16.
 func (p *parserMixin) Int16() (target *int16) {
 	target = new(int16)
@@ -457,6 +517,12 @@ func (f *int32Value) Get() interface{} { return (int32)(*f.v) }
 
 func (f *int32Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *int32Value) clone() Value { return newInt32Value(new(int32)) }
+
+func (f *int32Value) Reset() { *f.v = *new(int32) }
+
+func (f *int32Value) target() interface{} { return f.v }
+
 // Int32 parses the next command-line value as int32.
 func (p *parserMixin) Int32() (target *int32) {
 	target = new(int32)
@@ -500,6 +566,12 @@ func (f *int64Value) Get() interface{} { return (int64)(*f.v) }
 
 func (f *int64Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *int64Value) clone() Value { return newInt64Value(new(int64)) }
+
+func (f *int64Value) Reset() { *f.v = *new(int64) }
+
+func (f *int64Value) target() interface{} { return f.v }
+
 // Int64 parses the next command-line value as int64.
 func (p *parserMixin) Int64() (target *int64) {
 	target = new(int64)
@@ -543,6 +615,12 @@ func (f *float64Value) Get() interface{} { return (float64)(*f.v) }
 
 func (f *float64Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *float64Value) clone() Value { return newFloat64Value(new(float64)) }
+
+func (f *float64Value) Reset() { *f.v = *new(float64) }
+
+func (f *float64Value) target() interface{} { return f.v }
+
 // Float64 parses the next command-line value as float64.
 func (p *parserMixin) Float64() (target *float64) {
 	target = new(float64)
@@ -586,6 +664,12 @@ func (f *float32Value) Get() interface{} { return (float32)(*f.v) }
 
 func (f *float32Value) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *float32Value) clone() Value { return newFloat32Value(new(float32)) }
+
+func (f *float32Value) Reset() { *f.v = *new(float32) }
+
+func (f *float32Value) target() interface{} { return f.v }
+
 // Float32 parses the next command-line value as float32.
 func (p *parserMixin) Float32() (target *float32) {
 	target = new(float32)
@@ -707,6 +791,12 @@ func (f *regexpValue) Get() interface{} { return (*regexp.Regexp)(*f.v) }
 
 func (f *regexpValue) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *regexpValue) clone() Value { return newRegexpValue(new(*regexp.Regexp)) }
+
+func (f *regexpValue) Reset() { *f.v = *new(*regexp.Regexp) }
+
+func (f *regexpValue) target() interface{} { return f.v }
+
 // Regexp parses the next command-line value as *regexp.Regexp.
 func (p *parserMixin) Regexp() (target **regexp.Regexp) {
 	target = new(*regexp.Regexp)
@@ -750,6 +840,12 @@ func (f *resolvedIPValue) Get() interface{} { return (net.IP)(*f.v) }
 
 func (f *resolvedIPValue) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *resolvedIPValue) clone() Value { return newResolvedIPValue(new(net.IP)) }
+
+func (f *resolvedIPValue) Reset() { *f.v = *new(net.IP) }
+
+func (f *resolvedIPValue) target() interface{} { return f.v }
+
 // Resolve a hostname or IP to an IP.
 func (p *parserMixin) ResolvedIP() (target *net.IP) {
 	target = new(net.IP)
@@ -793,6 +889,12 @@ func (f *hexBytesValue) Get() interface{} { return ([]byte)(*f.v) }
 
 func (f *hexBytesValue) String() string { return fmt.Sprintf("%v", *f.v) }
 
+func (f *hexBytesValue) clone() Value { return newHexBytesValue(new([]byte)) }
+
+func (f *hexBytesValue) Reset() { *f.v = *new([]byte) }
+
+func (f *hexBytesValue) target() interface{} { return f.v }
+
 // Bytes as a hex string.
 func (p *parserMixin) HexBytes() (target *[]byte) {
 	target = new([]byte)
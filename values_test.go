@@ -2,6 +2,8 @@ package kingpin
 
 import (
 	"net"
+	"net/url"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -96,3 +98,27 @@ func TestSetValueDoesNotReset(t *testing.T) {
 	app.Flag("set", "").StringMapVar(&mapping)
 	assert.NotEmpty(t, mapping)
 }
+
+func TestValuesReset(t *testing.T) {
+	cases := []struct {
+		value Value
+		input string
+	}{
+		{newAccumulator(new([]string), func(v interface{}) Value { return newStringValue(v.(*string)) }), "a"},
+		{newDurationValue(new(time.Duration)), "1s"},
+		{newStringMapValue(&map[string]string{}), "k=v"},
+		{newCounterValue(new(int)), ""},
+		{newEnumFlag(new(string), "a"), "a"},
+		{newEnumsFlag(new([]string), "a"), "a"},
+		{newStringValue(new(string)), "a"},
+		{newIntValue(new(int)), "1"},
+		{newBoolValue(new(bool)), "true"},
+		{newURLListValue(new([]*url.URL)), "http://host"},
+	}
+	for _, c := range cases {
+		assert.NoError(t, c.value.Set(c.input))
+		assert.NotEmpty(t, c.value.(Getter).Get(), "%T", c.value)
+		c.value.(Resettable).Reset()
+		assert.Empty(t, c.value.(Getter).Get(), "%T", c.value)
+	}
+}